@@ -22,6 +22,7 @@ package config
 
 import (
 	"context"
+	"time"
 
 	"github.com/houseme/bytedance/utility/cache"
 	"github.com/houseme/bytedance/utility/logger"
@@ -60,6 +61,9 @@ type Config struct {
 	publicKey      string // 公钥
 	keyVersion     int    // 秘钥版本
 	keyType        Secret
+	baseURL        string        // 接口域名覆盖，用于测试环境或模拟服务
+	callbackSkew   time.Duration // 回调时间戳允许的偏差，0 表示不校验
+	nonceTTL       time.Duration // 回调 nonce 的保留时长，0 表示不校验
 	cache          cache.Cache
 	request        request.Request
 	logger         logger.ILogger
@@ -77,6 +81,9 @@ type options struct {
 	PublicKey      string // 公钥
 	KeyVersion     int    // 秘钥版本
 	KeyType        Secret
+	BaseURL        string        // 接口域名覆盖，用于测试环境或模拟服务
+	CallbackSkew   time.Duration // 回调时间戳允许的偏差，0 表示不校验
+	NonceTTL       time.Duration // 回调 nonce 的保留时长，0 表示不校验
 	Cache          cache.Cache
 	Logger         logger.ILogger
 	Request        request.Request
@@ -162,6 +169,28 @@ func WithKeyType(keyType Secret) Option {
 	}
 }
 
+// WithBaseURL set baseURL，所有接口请求的 scheme 与 host 会被替换为 baseURL；
+// baseURL 不合法时 NewE 返回错误，New 记录错误日志并忽略该设置
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.BaseURL = baseURL
+	}
+}
+
+// WithCallbackSkew set callbackSkew，回调 Byte-Timestamp 与当前时间的偏差超过 skew 时拒绝
+func WithCallbackSkew(skew time.Duration) Option {
+	return func(o *options) {
+		o.CallbackSkew = skew
+	}
+}
+
+// WithNonceTTL set nonceTTL，在 ttl 内重复出现的回调 Byte-Nonce-Str 会被拒绝，nonce 记录保存在 cache 中
+func WithNonceTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.NonceTTL = ttl
+	}
+}
+
 // WithLogger set logger
 func WithLogger(logger logger.ILogger) Option {
 	return func(o *options) {
@@ -183,8 +212,17 @@ func WithCache(cache cache.Cache) Option {
 	}
 }
 
-// New create config
+// New create config，WithBaseURL 设置的地址不合法时记录错误日志并忽略该设置，需要严格校验时使用 NewE
 func New(ctx context.Context, opts ...Option) *Config {
+	cfg, err := NewE(ctx, opts...)
+	if err != nil {
+		cfg.logger.Errorf(ctx, "config: ignore base url: %v", err)
+	}
+	return cfg
+}
+
+// NewE create config，WithBaseURL 设置的地址不合法时返回错误，同时返回忽略该设置后的 Config
+func NewE(ctx context.Context, opts ...Option) (*Config, error) {
 	op := options{
 		Logger:         logger.NewDefaultLogger(),
 		Request:        request.NewDefaultRequest(AccessTokenKey),
@@ -195,6 +233,16 @@ func New(ctx context.Context, opts ...Option) *Config {
 		option(&op)
 	}
 
+	var err error
+	if op.BaseURL != "" {
+		var req request.Request
+		if req, err = request.NewBaseURLRequest(op.BaseURL, op.Request); err != nil {
+			op.BaseURL = ""
+		} else {
+			op.Request = req
+		}
+	}
+
 	return &Config{
 		cacheKeyPrefix: op.CacheKeyPrefix,
 		clientKey:      op.ClientKey,
@@ -207,10 +255,13 @@ func New(ctx context.Context, opts ...Option) *Config {
 		publicKey:      op.PublicKey,
 		keyVersion:     op.KeyVersion,
 		keyType:        op.KeyType,
+		baseURL:        op.BaseURL,
+		callbackSkew:   op.CallbackSkew,
+		nonceTTL:       op.NonceTTL,
 		request:        op.Request,
 		logger:         op.Logger,
 		cache:          op.Cache,
-	}
+	}, err
 }
 
 // SetVersion 设置 version
@@ -285,6 +336,18 @@ func (cfg *Config) SetKeyType(keyType Secret) *Config {
 	return cfg
 }
 
+// SetCallbackSkew 设置 callbackSkew
+func (cfg *Config) SetCallbackSkew(skew time.Duration) *Config {
+	cfg.callbackSkew = skew
+	return cfg
+}
+
+// SetNonceTTL 设置 nonceTTL
+func (cfg *Config) SetNonceTTL(ttl time.Duration) *Config {
+	cfg.nonceTTL = ttl
+	return cfg
+}
+
 // NewConfig new config
 func NewConfig(ctx context.Context, clientKey, clientSecret, redirectURL, scopes, salt, token string) *Config {
 	return &Config{
@@ -306,9 +369,14 @@ func (cfg *Config) SetCache(cache cache.Cache) *Config {
 	return cfg
 }
 
-// SetRequest 设置请求
-func (cfg *Config) SetRequest(request request.Request) *Config {
-	cfg.request = request
+// SetRequest 设置请求，设置了 baseURL 时同样替换为 baseURL 后再交给 request 执行
+func (cfg *Config) SetRequest(req request.Request) *Config {
+	if cfg.baseURL != "" {
+		if wrapped, err := request.NewBaseURLRequest(cfg.baseURL, req); err == nil {
+			req = wrapped
+		}
+	}
+	cfg.request = req
 	return cfg
 }
 
@@ -378,6 +446,21 @@ func (cfg *Config) KeyType() Secret {
 	return cfg.keyType
 }
 
+// BaseURL 获取 baseURL
+func (cfg *Config) BaseURL() string {
+	return cfg.baseURL
+}
+
+// CallbackSkew 获取 callbackSkew
+func (cfg *Config) CallbackSkew() time.Duration {
+	return cfg.callbackSkew
+}
+
+// NonceTTL 获取 nonceTTL
+func (cfg *Config) NonceTTL() time.Duration {
+	return cfg.nonceTTL
+}
+
 // Cache 获取 cache
 func (cfg *Config) Cache() cache.Cache {
 	return cfg.cache
@@ -0,0 +1,52 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/houseme/bytedance/utility/cache"
+	"github.com/houseme/bytedance/utility/request"
+)
+
+func TestNewBaseURL(t *testing.T) {
+	ctx := context.Background()
+	if _, err := NewE(ctx, WithCache(cache.NewMemory()), WithBaseURL("127.0.0.1:8080")); err == nil {
+		t.Error("NewE() invalid base url error = nil")
+	}
+
+	cfg := New(ctx, WithCache(cache.NewMemory()), WithBaseURL("127.0.0.1:8080"))
+	if cfg.BaseURL() != "" {
+		t.Errorf("New() invalid BaseURL() = %q, want ignored", cfg.BaseURL())
+	}
+	if _, ok := cfg.Request().(*request.BaseURLRequest); ok {
+		t.Error("New() invalid base url still wraps the request")
+	}
+
+	cfg, err := NewE(ctx, WithCache(cache.NewMemory()), WithBaseURL("http://127.0.0.1:8080"))
+	if err != nil {
+		t.Fatalf("NewE() error = %v", err)
+	}
+	cfg.SetRequest(request.NewDefaultRequest(AccessTokenKey))
+	if _, ok := cfg.Request().(*request.BaseURLRequest); !ok {
+		t.Errorf("SetRequest() after WithBaseURL = %T, want *request.BaseURLRequest", cfg.Request())
+	}
+}
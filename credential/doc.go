@@ -24,6 +24,7 @@ import (
 	"context"
 
 	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/utility/helper"
 )
 
 // ContextConfig 公共配置
@@ -38,6 +39,13 @@ func (cfg *ContextConfig) SetAccessTokenHandle(accessTokenHandle AccessTokenHand
 	return cfg
 }
 
+// ReplayGuard 回调防重放校验，每次按 Config 当前的缓存、时间戳偏差与 nonce 保留时长创建，
+// 运行中修改这些配置对之后的回调立即生效
+func (cfg *ContextConfig) ReplayGuard() *helper.ReplayGuard {
+	c := cfg.Config
+	return helper.NewReplayGuard(c.Cache(), c.CacheKeyPrefix(), c.CallbackSkew(), c.NonceTTL())
+}
+
 // NewContextConfigWithConfig new context config with config
 func NewContextConfigWithConfig(ctx context.Context, cfg *config.Config) *ContextConfig {
 	ctxCfg := &ContextConfig{
@@ -0,0 +1,48 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package credential
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/utility/base"
+	"github.com/houseme/bytedance/utility/cache"
+)
+
+func TestContextConfigReplayGuard(t *testing.T) {
+	ctx := context.Background()
+	cfg := &ContextConfig{Config: config.New(ctx, config.WithCache(cache.NewMemory()), config.WithNonceTTL(0))}
+	if _, err := cfg.ReplayGuard().Check(ctx, "1", "n1"); err != nil {
+		t.Fatalf("Check() nonce disabled error = %v", err)
+	}
+
+	// 运行中开启 nonce 校验，之后的回调立即生效
+	cfg.SetNonceTTL(time.Hour)
+	if _, err := cfg.ReplayGuard().Check(ctx, "1", "n1"); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if _, err := cfg.ReplayGuard().Check(ctx, "1", "n1"); !errors.Is(err, base.ErrCallbackNonceReused) {
+		t.Errorf("Check() replay error = %v, want ErrCallbackNonceReused", err)
+	}
+}
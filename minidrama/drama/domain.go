@@ -19,6 +19,8 @@
 
 package drama
 
+import "github.com/houseme/bytedance/utility/helper"
+
 // UploadImageRequest 资源上传 图片
 type UploadImageRequest struct {
     ResourceType int        `json:"resource_type"`
@@ -449,4 +451,6 @@ type AsyncResponse struct {
     AlbumAudit   *AsyncAlbumAudit   `json:"album_audit"`
     EpisodeAudit *AsyncEpisodeAudit `json:"episode_audit"`
     UploadVideo  *AsyncUploadVideo  `json:"upload_video"`
+    
+    claim *helper.ReplayClaim
 }
@@ -23,6 +23,7 @@ package drama
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"strings"
 
 	"github.com/houseme/bytedance/config"
@@ -31,6 +32,36 @@ import (
 	"github.com/houseme/bytedance/utility/helper"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type IDrama -impl Drama
+
+// IDrama Drama 服务接口，便于替换为 FakeDrama 等测试替身
+type IDrama interface {
+	// UploadImage 上传图片
+	UploadImage(ctx context.Context, req *UploadImageRequest) (*UploadImageResponse, error)
+	// UploadVideo 上传视频
+	UploadVideo(ctx context.Context, req *UploadVideoRequest) (*UploadVideoResponse, error)
+	// QueryVideo 查询视频
+	QueryVideo(ctx context.Context, req *QueryVideoRequest) (*QueryVideoResponse, error)
+	// CreateVideo 创建视频
+	CreateVideo(ctx context.Context, req *CreateVideoRequest) (*CreateVideoResponse, error)
+	// EditVideo 编辑视频
+	EditVideo(ctx context.Context, req *EditVideoRequest) (*EditVideoResponse, error)
+	// QueryVideoAlbum 查询视频专辑
+	QueryVideoAlbum(ctx context.Context, req *QueryVideoAlbumRequest) (*QueryVideoAlbumResponse, error)
+	// ReviewVideo 审核视频 短剧送审
+	ReviewVideo(ctx context.Context, req *ReviewVideoRequest) (*ReviewVideoResponse, error)
+	// AuthorizeVideo 短剧授权
+	AuthorizeVideo(ctx context.Context, req *AuthorizeVideoRequest) (*AuthorizeVideoResponse, error)
+	// OnlineAlbum 上线视频专辑
+	OnlineAlbum(ctx context.Context, req *OnlineAlbumRequest) (*OnlineAlbumResponse, error)
+	// BindAlbum 绑定视频专辑 页面绑定
+	BindAlbum(ctx context.Context, req *BindAlbumRequest) (*BindAlbumResponse, error)
+	// PlayInfo 获取视频播放信息
+	PlayInfo(ctx context.Context, req *PlayInfoRequest) (*PlayInfoResponse, error)
+	// AsyncNotify 异步通知
+	AsyncNotify(ctx context.Context, req *AsyncRequest) (*AsyncResponse, error)
+}
+
 // Drama mini drama
 type Drama struct {
 	ctxCfg *credential.ContextConfig
@@ -338,7 +369,7 @@ func (d *Drama) PlayInfo(ctx context.Context, req *PlayInfoRequest) (resp *PlayI
 	return
 }
 
-// AsyncNotify 异步通知
+// AsyncNotify 异步通知，验签或防重放校验未通过时返回 err_no 400 的 resp，err 为 nil
 func (d *Drama) AsyncNotify(ctx context.Context, req *AsyncRequest) (resp *AsyncResponse, err error) {
 	if req == nil {
 		return nil, base.ErrRequestIsEmpty
@@ -363,6 +394,25 @@ func (d *Drama) AsyncNotify(ctx context.Context, req *AsyncRequest) (resp *Async
 		return
 	}
 
+	var claim *helper.ReplayClaim
+	if claim, err = d.ctxCfg.ReplayGuard().Check(ctx, req.ByteTimestamp, req.ByteNonceStr); err != nil {
+		if !errors.Is(err, base.ErrCallbackNonceReused) && !errors.Is(err, base.ErrCallbackTimestampExpired) {
+			resp.ErrNo = ErrNoSystemError
+			resp.ErrTips = ErrTipsSystemError
+			return
+		}
+		resp.ErrNo = ErrNoFailedToCheckTheSignature
+		resp.ErrTips = "replay check failed"
+		return resp, nil
+	}
+	defer func() {
+		if err != nil {
+			_ = claim.Finish(ctx, err)
+			return
+		}
+		resp.claim = claim
+	}()
+
 	if req.Type == AlbumAudit {
 		var data = new(AsyncAlbumAudit)
 		if err = json.Unmarshal([]byte(req.Msg), data); err != nil {
@@ -395,3 +445,8 @@ func (d *Drama) AsyncNotify(ctx context.Context, req *AsyncRequest) (resp *Async
 
 	return
 }
+
+// Finish 业务处理结束后调用：回调 nonce 已在校验时记录，handleErr 非 nil 时释放以便平台重试；返回 handleErr 与释放 nonce 的错误
+func (r *AsyncResponse) Finish(ctx context.Context, handleErr error) error {
+	return r.claim.Finish(ctx, handleErr)
+}
@@ -65,11 +65,11 @@ func (d *MiniDrama) ContextConfig() *credential.ContextConfig {
 }
 
 // Drama return drama
-func (d *MiniDrama) Drama() *drama.Drama {
+func (d *MiniDrama) Drama() drama.IDrama {
 	return drama.NewDrama(d.ContextConfig())
 }
 
 // Voc return voc
-func (d *MiniDrama) Voc() *voc.Voc {
+func (d *MiniDrama) Voc() voc.IVoc {
 	return voc.NewVoc(d.ContextConfig())
 }
@@ -30,6 +30,26 @@ import (
 	"github.com/houseme/bytedance/utility/base"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type IVoc -impl Voc
+
+// IVoc Voc 服务接口，便于替换为 FakeVoc 等测试替身
+type IVoc interface {
+	// QueryVideoList 查询视频列表
+	QueryVideoList(ctx context.Context, req *QueryListRequest) (*QueryListResponse, error)
+	// DeleteVideo 删除视频
+	DeleteVideo(ctx context.Context, req *DeleteVideoRequest) (*DeleteVideoResponse, error)
+	// QueryVideoURL 获取视频播放地址
+	QueryVideoURL(ctx context.Context, req *QueryVideoURLRequest) (*QueryVideoURLResponse, error)
+	// BatchUploadVideoByURL 批量上传视频
+	BatchUploadVideoByURL(ctx context.Context, req *UploadByURLRequest) (*UploadByURLResponse, error)
+	// QueryUploadVideoJobInfo 查询视频状态
+	QueryUploadVideoJobInfo(ctx context.Context, req *QueryUploadByURLRequest) (*QueryUploadByURLResponse, error)
+	// StartWorkFlow 发起转码处理
+	StartWorkFlow(ctx context.Context, req *StartWorkFlowRequest) (*StartWorkFlowResponse, error)
+	// QueryWorkFlow 查询转码状态
+	QueryWorkFlow(ctx context.Context, req *QueryWorkFlowRequest) (*QueryWorkFlowResponse, error)
+}
+
 // Voc mini drama
 type Voc struct {
 	ctxCfg *credential.ContextConfig
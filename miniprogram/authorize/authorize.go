@@ -37,6 +37,20 @@ const (
 	jsCode2sessionURL = "https://developer.toutiao.com/api/apps/v2/jscode2session"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type IAuthorize -impl Authorize
+
+// IAuthorize Authorize 服务接口，便于替换为 FakeAuthorize 等测试替身
+type IAuthorize interface {
+	// GetRedirectURL 获取授权码的 URL 地址
+	GetRedirectURL(ctx context.Context, state string) string
+	// GetSilenceOauthURL 获取静默授权码的 URL 地址
+	GetSilenceOauthURL(ctx context.Context, state string) string
+	// GetUserAccessToken 通过网页授权的 code 换取 access_token
+	GetUserAccessToken(ctx context.Context, code string) (credential.AccessToken, error)
+	// CodeToSession 获取用户的 session_key 和 openid
+	CodeToSession(ctx context.Context, code string, anonymousCode string) (CodeToSessionData, error)
+}
+
 // Authorize 保存用户授权信息
 type Authorize struct {
 	*credential.ContextConfig
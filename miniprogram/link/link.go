@@ -111,6 +111,20 @@ type QueryLinkData struct {
 	ExpireTime int    `json:"expire_time"`
 }
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type ILink -impl Link
+
+// ILink Link 服务接口，便于替换为 FakeLink 等测试替身
+type ILink interface {
+	// Generate generate short link
+	Generate(ctx context.Context, req *GenerateV1Request) (*GenerateV1Response, error)
+	// GenerateV2 generate short link v2
+	GenerateV2(ctx context.Context, req *GenerateLinkRequest) (*GenerateLinkResponse, error)
+	// QueryQuotaV2 query link quota v2
+	QueryQuotaV2(ctx context.Context, req *QueryLinkQuotaRequest) (*QueryLinkQuotaResponse, error)
+	// QueryV2 query link v2
+	QueryV2(ctx context.Context, req *QueryLinkRequest) (*QueryLinkResponse, error)
+}
+
 // Link short link relation
 type Link struct {
 	ctxCfg *credential.ContextConfig
@@ -80,26 +80,26 @@ func (ma *MicroApp) GetClientToken(ctx context.Context) (string, error) {
 }
 
 // GetAuthorize oauth2 网页授权
-func (ma *MicroApp) GetAuthorize() *authorize.Authorize {
+func (ma *MicroApp) GetAuthorize() authorize.IAuthorize {
 	return authorize.NewAuthorize(ma.ctxCfg)
 }
 
 // GetQrcode 获取小程序码
-func (ma *MicroApp) GetQrcode() *qrcode.QRCode {
+func (ma *MicroApp) GetQrcode() qrcode.IQRCode {
 	return qrcode.NewQRCode(ma.ctxCfg)
 }
 
 // GetLink 获取小程序 link
-func (ma *MicroApp) GetLink() *link.Link {
+func (ma *MicroApp) GetLink() link.ILink {
 	return link.New(ma.ctxCfg)
 }
 
 // GetSchema 获取小程序 schema
-func (ma *MicroApp) GetSchema() *schema.Schema {
+func (ma *MicroApp) GetSchema() schema.ISchema {
 	return schema.New(ma.ctxCfg)
 }
 
 // GetSolution 获取小程序解决方案
-func (ma *MicroApp) GetSolution() *solution.Solution {
+func (ma *MicroApp) GetSolution() solution.ISolution {
 	return solution.NewSolution(ma.ctxCfg)
 }
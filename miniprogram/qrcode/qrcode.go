@@ -29,6 +29,14 @@ import (
 	"github.com/houseme/bytedance/utility/base"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type IQRCode -impl QRCode
+
+// IQRCode QRCode 服务接口，便于替换为 FakeQRCode 等测试替身
+type IQRCode interface {
+	// FetchCode 获取小程序码
+	FetchCode(ctx context.Context, data QRCoder) ([]byte, error)
+}
+
 // QRCode 小程序码
 type QRCode struct {
 	*credential.ContextConfig
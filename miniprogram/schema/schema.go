@@ -98,6 +98,18 @@ type QuerySchemaQuotaData struct {
 	ShortTermSchemaQuota TermSchemaQuota `json:"short_term_schema_quota"`
 }
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type ISchema -impl Schema
+
+// ISchema Schema 服务接口，便于替换为 FakeSchema 等测试替身
+type ISchema interface {
+	// Generate generate schema
+	Generate(ctx context.Context, request *GenerateSchemaRequest) (*GenerateSchemaResponse, error)
+	// Query query schema
+	Query(ctx context.Context, request *QuerySchemaRequest) (*QuerySchemaResponse, error)
+	// QueryQuota query schema quota
+	QueryQuota(ctx context.Context, request *QuerySchemaQuotaRequest) (*QuerySchemaQuotaResponse, error)
+}
+
 // Schema create schema
 type Schema struct {
 	ctxCfg *credential.ContextConfig
@@ -29,6 +29,16 @@ import (
 	"github.com/houseme/bytedance/utility/base"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type ISolution -impl Solution
+
+// ISolution Solution 服务接口，便于替换为 FakeSolution 等测试替身
+type ISolution interface {
+	// CreateSolution 创建解决方案
+	CreateSolution(ctx context.Context, req *CreateSolutionRequest) (*CreateSolutionResponse, error)
+	// QuerySolution 查询解决方案
+	QuerySolution(ctx context.Context, req *QuerySolutionRequest) (*QuerySolutionResponse, error)
+}
+
 // Solution 解决方案
 type Solution struct {
 	ctxCfg *credential.ContextConfig
@@ -22,11 +22,25 @@ package asyncnotify
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 
 	"github.com/houseme/bytedance/credential"
+	"github.com/houseme/bytedance/utility/base"
 	"github.com/houseme/bytedance/utility/helper"
 )
 
+// ErrUnknownType 未知的回调类型
+var ErrUnknownType = errors.New("asyncnotify: unknown callback type")
+
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type IAsyncNotify -impl AsyncNotify
+
+// IAsyncNotify AsyncNotify 服务接口，便于替换为 FakeAsyncNotify 等测试替身
+type IAsyncNotify interface {
+	// AsyncNotify 异步通知
+	AsyncNotify(ctx context.Context, req *AsyncRequest) (*AsyncResponse, error)
+}
+
 // AsyncNotify async notify
 type AsyncNotify struct {
 	ctxCfg *credential.ContextConfig
@@ -37,7 +51,7 @@ func NewAsyncNotify(cfg *credential.ContextConfig) *AsyncNotify {
 	return &AsyncNotify{ctxCfg: cfg}
 }
 
-// AsyncNotify 异步通知
+// AsyncNotify 异步通知，验签或防重放校验未通过时返回 err_no 400 的 resp，err 为 nil
 func (a *AsyncNotify) AsyncNotify(ctx context.Context, req *AsyncRequest) (resp *AsyncResponse, err error) {
 	a.ctxCfg.Logger().Debug(ctx, " async notify request params:", req)
 	resp = &AsyncResponse{
@@ -64,25 +78,62 @@ func (a *AsyncNotify) AsyncNotify(ctx context.Context, req *AsyncRequest) (resp
 		resp.ErrTips = "failed"
 		return
 	}
-	if req.Type == AsyncPay {
-		var data = new(PaymentData)
-		if err = json.Unmarshal([]byte(req.Msg), data); err != nil {
+
+	var claim *helper.ReplayClaim
+	if claim, err = a.ctxCfg.ReplayGuard().Check(ctx, req.ByteTimestamp, req.ByteNonceStr); err != nil {
+		if !errors.Is(err, base.ErrCallbackNonceReused) && !errors.Is(err, base.ErrCallbackTimestampExpired) {
 			resp.ErrNo = ErrNoSystemError
-			resp.ErrTips = ErrTipsSystemError + err.Error()
+			resp.ErrTips = ErrTipsSystemError
 			return
 		}
-		resp.PaymentData = data
+		resp.ErrNo = ErrNoFailedToCheckTheSignature
+		resp.ErrTips = "replay check failed"
+		return resp, nil
 	}
-
-	if req.Type == AsyncSettle {
-		var data = new(SettleData)
-		if err = json.Unmarshal([]byte(req.Msg), data); err != nil {
-			resp.ErrNo = ErrNoSystemError
-			resp.ErrTips = ErrTipsSystemError + err.Error()
+	defer func() {
+		if err != nil {
+			_ = claim.Finish(ctx, err)
 			return
 		}
-		resp.SettleData = data
+		resp.claim = claim
+	}()
+	switch req.Type {
+	case AsyncPay:
+		resp.PaymentData = new(PaymentData)
+		err = json.Unmarshal([]byte(req.Msg), resp.PaymentData)
+	case AsyncSettle:
+		resp.SettleData = new(SettleData)
+		err = json.Unmarshal([]byte(req.Msg), resp.SettleData)
+	case AsyncRefund:
+		resp.RefundData = new(RefundData)
+		err = json.Unmarshal([]byte(req.Msg), resp.RefundData)
+	case AsyncRefundAudit:
+		resp.RefundAuditData = new(RefundAuditData)
+		err = json.Unmarshal([]byte(req.Msg), resp.RefundAuditData)
+	case AsyncWithdraw:
+		resp.WithdrawData = new(WithdrawData)
+		err = json.Unmarshal([]byte(req.Msg), resp.WithdrawData)
+	case AsyncTransfer:
+		resp.TransferData = new(TransferData)
+		err = json.Unmarshal([]byte(req.Msg), resp.TransferData)
+	case AsyncSettleFinish:
+		resp.SettleFinishData = new(SettleFinishData)
+		err = json.Unmarshal([]byte(req.Msg), resp.SettleFinishData)
+	default:
+		resp.ErrNo = ErrNoRequestParameterError
+		resp.ErrTips = "unknown callback type: " + req.Type
+		return resp, fmt.Errorf("%w: %q", ErrUnknownType, req.Type)
+	}
+	if err != nil {
+		resp.ErrNo = ErrNoSystemError
+		resp.ErrTips = ErrTipsSystemError + err.Error()
+		return
 	}
 
 	return
 }
+
+// Finish 业务处理结束后调用：回调 nonce 已在校验时记录，handleErr 非 nil 时释放以便平台重试；返回 handleErr 与释放 nonce 的错误
+func (r *AsyncResponse) Finish(ctx context.Context, handleErr error) error {
+	return r.claim.Finish(ctx, handleErr)
+}
@@ -63,4 +63,16 @@ const (
     
     // AsyncSettleFinish 异步结算完成类型 自动分账
     AsyncSettleFinish = "settle_finish"
+    
+    // AsyncRefundAudit 退款申请回调类型，开启退款审核时需在应答中返回审核结果
+    AsyncRefundAudit = "pre_create_refund"
+)
+
+const (
+    // RefundAuditPending 暂不审核，稍后通过退款审核接口提交审核结果
+    RefundAuditPending = 0
+    // RefundAuditApprove 同意退款
+    RefundAuditApprove = 1
+    // RefundAuditReject 拒绝退款
+    RefundAuditReject = 2
 )
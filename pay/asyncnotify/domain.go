@@ -19,6 +19,8 @@
 
 package asyncnotify
 
+import "github.com/houseme/bytedance/utility/helper"
+
 // AsyncRequest 异步通知
 type AsyncRequest struct {
     Content          string `json:"content" description:"回调内容，应答中的报文主体（response body）"`
@@ -39,6 +41,16 @@ type AsyncResponse struct {
     Type        string       `json:"type" description:"回调类型（支付结果回调为 payment）：payment（支付成功/支付取消）"`
     PaymentData *PaymentData `json:"paymentData"`
     SettleData  *SettleData  `json:"settleData"`
+    RefundData  *RefundData  `json:"refundData"`
+    
+    WithdrawData     *WithdrawData     `json:"withdrawData"`
+    TransferData     *TransferData     `json:"transferData"`
+    SettleFinishData *SettleFinishData `json:"settleFinishData"`
+    
+    RefundAuditData     *RefundAuditData     `json:"refundAuditData"`
+    RefundAuditDecision *RefundAuditDecision `json:"refundAuditDecision" description:"退款审核结果，由业务方设置后作为应答 data 返回"`
+    
+    claim *helper.ReplayClaim
 }
 
 // PaymentData 异步通知
@@ -74,3 +86,110 @@ type SettleData struct {
     ItemOrderID  string `json:"item_order_id"`
     IsAutoSettle bool   `json:"is_auto_settle" desc:"是否自动分账 "`
 }
+
+// RefundData 退款结果异步信息
+type RefundData struct {
+    AppID             string             `json:"app_id" description:"小程序 app_id"`
+    Status            string             `json:"status" description:"退款状态：SUCCESS：退款成功，FAIL：退款失败"`
+    OrderID           string             `json:"order_id" description:"抖音开平侧订单号"`
+    CpExtra           string             `json:"cp_extra"`
+    Message           string             `json:"message" description:"退款结果信息，可以通过该字段了解退款失败原因"`
+    EventTime         int64              `json:"event_time" description:"退款成功/失败时间戳，单位为毫秒"`
+    RefundID          string             `json:"refund_id" description:"抖音开平侧退款单号"`
+    OutRefundNo       string             `json:"out_refund_no" description:"开发者侧退款单号"`
+    RefundTotalAmount int                `json:"refund_total_amount" description:"退款总金额，单位分"`
+    IsAllSettled      bool               `json:"is_all_settled" description:"退款时订单是否已全部分账"`
+    ItemOrderDetail   []*RefundItemOrder `json:"item_order_detail"`
+}
+
+// RefundItemOrder 商品单退款信息
+type RefundItemOrder struct {
+    ItemOrderID  string `json:"item_order_id"`
+    RefundAmount int    `json:"refund_amount" description:"该商品单退款金额，单位分"`
+}
+
+// RefundAuditData 退款申请（审核）回调信息
+type RefundAuditData struct {
+    AppID               string             `json:"app_id" description:"小程序 app_id"`
+    OrderID             string             `json:"order_id" description:"抖音开平侧订单号"`
+    OutOrderNo          string             `json:"out_order_no" description:"开发者侧订单号"`
+    RefundID            string             `json:"refund_id" description:"抖音开平侧退款单号"`
+    RefundTotalAmount   int                `json:"refund_total_amount" description:"退款总金额，单位分"`
+    NeedRefundAudit     int                `json:"need_refund_audit" description:"是否需要退款审核，1：需要审核，2：不需要审核"`
+    RefundAuditDeadline int64              `json:"refund_audit_deadline" description:"退款审核的最后期限，13 位毫秒时间戳，过期未审核将自动同意退款"`
+    RefundReason        []*RefundReason    `json:"refund_reason" description:"退款原因"`
+    RefundSource        int                `json:"refund_source" description:"退款来源，1：用户发起，2：客服发起"`
+    CreateRefundTime    int64              `json:"create_refund_time" description:"退款申请时间，13 位毫秒时间戳"`
+    CpExtra             string             `json:"cp_extra"`
+    ItemOrderDetail     []*RefundItemOrder `json:"item_order_detail"`
+}
+
+// RefundReason 退款原因
+type RefundReason struct {
+    Code int    `json:"code"`
+    Text string `json:"text"`
+}
+
+// RefundAuditDecision 退款审核结果
+type RefundAuditDecision struct {
+    OutRefundNo       string  `json:"out_refund_no" description:"开发者侧退款单号，长度 <= 64byte"`
+    OrderEntrySchema  *Schema `json:"order_entry_schema,omitempty" description:"退款单详情页 schema"`
+    NotifyURL         string  `json:"notify_url,omitempty" description:"退款结果通知地址，不传使用小程序配置的回调地址"`
+    RefundAuditStatus int     `json:"refund_audit_status,omitempty" description:"审核结果，1：同意退款，2：拒绝退款，不传表示稍后异步提交"`
+    DenyMessage       string  `json:"deny_message,omitempty" description:"拒绝退款的原因，拒绝时必填，长度 <= 50 个字符"`
+}
+
+// Schema 页面跳转协议
+type Schema struct {
+    Path   string `json:"path"`
+    Params string `json:"params"`
+}
+
+// RefundAuditAck 退款申请回调应答
+type RefundAuditAck struct {
+    ErrNo   int                  `json:"err_no"`
+    ErrTips string               `json:"err_tips"`
+    Data    *RefundAuditDecision `json:"data,omitempty"`
+}
+
+// WithdrawData 提现结果异步信息
+type WithdrawData struct {
+    AppID          string `json:"app_id" description:"小程序 app_id"`
+    Status         string `json:"status" description:"提现状态：SUCCESS：提现成功，FAIL：提现失败，REEXCHANGE：退票"`
+    OrderID        string `json:"order_id" description:"抖音开平侧提现单号"`
+    OutOrderID     string `json:"out_order_id" description:"开发者侧提现单号"`
+    MerchantUID    string `json:"merchant_uid" description:"提现商户号"`
+    ChannelType    string `json:"channel_type" description:"提现渠道：wx、alipay、hz、yeepay"`
+    WithdrawAmount int    `json:"withdraw_amount" description:"提现金额，单位分"`
+    Message        string `json:"message" description:"提现结果信息，可以通过该字段了解提现失败原因"`
+    EventTime      int64  `json:"event_time" description:"提现成功/失败时间戳，单位为毫秒"`
+    CpExtra        string `json:"cp_extra"`
+}
+
+// TransferData 转账结果异步信息
+type TransferData struct {
+    AppID          string `json:"app_id" description:"小程序 app_id"`
+    Status         string `json:"status" description:"转账状态：SUCCESS：转账成功，FAIL：转账失败"`
+    OrderID        string `json:"order_id" description:"抖音开平侧转账单号"`
+    OutOrderNo     string `json:"out_order_no" description:"开发者侧转账单号"`
+    TransferAmount int    `json:"transfer_amount" description:"转账金额，单位分"`
+    Message        string `json:"message" description:"转账结果信息，可以通过该字段了解转账失败原因"`
+    EventTime      int64  `json:"event_time" description:"转账成功/失败时间戳，单位为毫秒"`
+    CpExtra        string `json:"cp_extra"`
+}
+
+// SettleFinishData 自动分账完成异步信息
+type SettleFinishData struct {
+    AppID        string `json:"app_id" description:"小程序 app_id"`
+    Status       string `json:"status" description:"分账状态：SUCCESS：分账成功，FAIL：分账失败"`
+    OrderID      string `json:"order_id" description:"抖音开平侧订单号"`
+    OutOrderNo   string `json:"out_order_no" description:"开发者侧订单号"`
+    SettleID     string `json:"settle_id" description:"抖音开平侧分账单号"`
+    SettleAmount int    `json:"settle_amount" description:"分账金额，单位分"`
+    Rake         int    `json:"rake" description:"平台手续费，单位分"`
+    Commission   int    `json:"commission" description:"交易参与 CPS 投放等任务产生的佣金，单位分"`
+    SettleDetail string `json:"settle_detail" description:"分账细节"`
+    Message      string `json:"message" description:"分账结果信息"`
+    EventTime    int64  `json:"event_time" description:"分账完成时间戳，单位为毫秒"`
+    CpExtra      string `json:"cp_extra"`
+}
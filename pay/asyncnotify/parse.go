@@ -0,0 +1,72 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package asyncnotify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+const (
+	// HeaderIdentifyName 回调标识请求头
+	HeaderIdentifyName = "Byte-Identifyname"
+	// HeaderLogID 回调日志 ID 请求头
+	HeaderLogID = "Byte-Logid"
+	// HeaderNonceStr 随机字符串请求头
+	HeaderNonceStr = "Byte-Nonce-Str"
+	// HeaderSignature 签名请求头
+	HeaderSignature = "Byte-Signature"
+	// HeaderTimestamp 时间戳请求头
+	HeaderTimestamp = "Byte-Timestamp"
+
+	// maxBodySize 回调报文的最大长度
+	maxBodySize = 1 << 20
+)
+
+// ParseRequest 将回调请求解析为 AsyncRequest，签名相关字段取自 Byte-* 请求头
+func ParseRequest(r *http.Request) (*AsyncRequest, error) {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		return nil, err
+	}
+	var env struct {
+		Version string `json:"version"`
+		Msg     string `json:"msg"`
+		Type    string `json:"type"`
+	}
+	if err = json.Unmarshal(body, &env); err != nil {
+		return nil, err
+	}
+	return &AsyncRequest{
+		Content:          string(body),
+		Version:          env.Version,
+		Msg:              env.Msg,
+		Type:             env.Type,
+		ByteIdentifyName: r.Header.Get(HeaderIdentifyName),
+		ByteLogID:        r.Header.Get(HeaderLogID),
+		ByteNonceStr:     r.Header.Get(HeaderNonceStr),
+		ByteSignature:    r.Header.Get(HeaderSignature),
+		ByteTimestamp:    r.Header.Get(HeaderTimestamp),
+	}, nil
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package asyncnotify
+
+import (
+	"errors"
+)
+
+// ApproveRefund 同意退款
+func ApproveRefund(outRefundNo string) *RefundAuditDecision {
+	return &RefundAuditDecision{OutRefundNo: outRefundNo, RefundAuditStatus: RefundAuditApprove}
+}
+
+// RejectRefund 拒绝退款，reason 为展示给用户的拒绝原因
+func RejectRefund(outRefundNo, reason string) *RefundAuditDecision {
+	return &RefundAuditDecision{OutRefundNo: outRefundNo, RefundAuditStatus: RefundAuditReject, DenyMessage: reason}
+}
+
+// DeferRefundAudit 暂不审核，需在 refund_audit_deadline 前通过 refund.Refund.AuditRefund 提交审核结果
+func DeferRefundAudit(outRefundNo string) *RefundAuditDecision {
+	return &RefundAuditDecision{OutRefundNo: outRefundNo, RefundAuditStatus: RefundAuditPending}
+}
+
+// Validate 校验审核结果
+func (d *RefundAuditDecision) Validate() error {
+	if d.OutRefundNo == "" {
+		return errors.New("refund audit: out_refund_no is empty")
+	}
+	switch d.RefundAuditStatus {
+	case RefundAuditPending, RefundAuditApprove:
+	case RefundAuditReject:
+		if d.DenyMessage == "" {
+			return errors.New("refund audit: deny_message is required when rejecting")
+		}
+	default:
+		return errors.New("refund audit: unknown refund_audit_status")
+	}
+	return nil
+}
+
+// RefundAuditAck 生成退款申请回调的应答，验签失败时原样返回错误码，未设置审核结果或审核结果不合法时返回系统错误，平台会重试回调
+func (r *AsyncResponse) RefundAuditAck() *RefundAuditAck {
+	if r.ErrNo != ErrNoSuccess {
+		return &RefundAuditAck{ErrNo: r.ErrNo, ErrTips: r.ErrTips}
+	}
+	if r.RefundAuditDecision == nil {
+		return &RefundAuditAck{ErrNo: ErrNoSystemError, ErrTips: "refund audit decision is empty"}
+	}
+	if err := r.RefundAuditDecision.Validate(); err != nil {
+		return &RefundAuditAck{ErrNo: ErrNoSystemError, ErrTips: err.Error()}
+	}
+	return &RefundAuditAck{ErrNo: ErrNoSuccess, ErrTips: ErrTipsSuccess, Data: r.RefundAuditDecision}
+}
@@ -0,0 +1,291 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+// Package autowithdraw 自动提现：按策略查询各商户、渠道的可提现余额并发起提现
+package autowithdraw
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/houseme/bytedance/pay/withdraw"
+)
+
+const (
+	// defaultPrefix 默认提现单号前缀
+	defaultPrefix = "aw"
+
+	// SkipNotScheduled 不在提现时间内
+	SkipNotScheduled = "not scheduled"
+	// SkipBelowThreshold 可提现余额未超过阈值
+	SkipBelowThreshold = "below threshold"
+	// SkipBelowMinimum 扣除保留金额后低于最小提现金额
+	SkipBelowMinimum = "below minimum amount"
+)
+
+// Account 提现账户
+type Account struct {
+	MerchantUID    string
+	ChannelType    string // withdraw.Alipay、withdraw.Wx、withdraw.Hz、withdraw.Yzt
+	MerchantEntity int    // withdraw.MerchantEntityDy 或 withdraw.MerchantEntityGh
+	AppID          string
+	ThirdPartyID   string
+}
+
+// Policy 提现策略，金额单位分
+type Policy struct {
+	Threshold int                  // 可提现余额超过该值才提现
+	Reserve   int                  // 提现后保留在账户中的金额
+	MinAmount int                  // 单次最小提现金额
+	MaxAmount int                  // 单次最大提现金额，0 表示不限
+	Schedule  func(time.Time) bool // 是否允许在该时间提现，传入北京时间，nil 表示不限，如 BusinessDays
+}
+
+// Rule 账户与提现策略
+type Rule struct {
+	Account Account
+	Policy  Policy
+}
+
+// beijing 工作日与节假日按北京时间判断，与服务器时区无关
+var beijing = time.FixedZone("CST", 8*60*60)
+
+// BusinessDays 北京时间周一至周五
+func BusinessDays(t time.Time) bool {
+	t = t.In(beijing)
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+// BusinessDaysExcept 北京时间周一至周五且不在 holidays 中的日期，holidays 按其自身时区的日期计
+func BusinessDaysExcept(holidays ...time.Time) func(time.Time) bool {
+	days := make(map[string]struct{}, len(holidays))
+	for _, h := range holidays {
+		days[h.Format(time.DateOnly)] = struct{}{}
+	}
+	return func(t time.Time) bool {
+		_, holiday := days[t.In(beijing).Format(time.DateOnly)]
+		return BusinessDays(t) && !holiday
+	}
+}
+
+// Plan 单个账户的提现计划与执行结果
+type Plan struct {
+	Account    Account
+	Balance    int    // 可提现余额
+	Amount     int    // 计划提现金额
+	OutOrderID string // 本次运行生成的提现单号
+	Skipped    string // 跳过原因，为空表示计划提现
+	Applied    bool   // 是否已发起提现
+	OrderID    string // 平台提现单号
+	Err        error
+}
+
+// Report 自动提现报告
+type Report struct {
+	RunID  string
+	DryRun bool
+	At     time.Time
+	Plans  []*Plan
+	Amount int // 计划（dry-run）或已发起的提现总额
+	Failed int
+}
+
+// Engine 自动提现
+type Engine struct {
+	withdraw withdraw.IWithdraw
+	tracker  *withdraw.Tracker
+	prefix   string
+	now      func() time.Time
+}
+
+type options struct {
+	tracker *withdraw.Tracker
+	prefix  string
+	now     func() time.Time
+}
+
+// Option engine option
+type Option func(*options)
+
+// WithTracker 通过 withdraw.Tracker 发起提现，便于后续跟踪状态与退票
+func WithTracker(tracker *withdraw.Tracker) Option {
+	return func(o *options) {
+		o.tracker = tracker
+	}
+}
+
+// WithPrefix 设置提现单号前缀
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithClock 设置时间来源
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// New 创建自动提现，w 通常为 pay.Pay.Withdraw()
+func New(w withdraw.IWithdraw, opts ...Option) *Engine {
+	op := options{
+		prefix: defaultPrefix,
+		now:    time.Now,
+	}
+	for _, option := range opts {
+		option(&op)
+	}
+	return &Engine{
+		withdraw: w,
+		tracker:  op.tracker,
+		prefix:   op.prefix,
+		now:      op.now,
+	}
+}
+
+// DryRun 只查询余额并计算提现计划，不发起提现
+func (e *Engine) DryRun(ctx context.Context, rules []Rule) *Report {
+	return e.run(ctx, rules, true)
+}
+
+// Run 按策略发起提现，单个账户失败不影响其他账户
+func (e *Engine) Run(ctx context.Context, rules []Rule) *Report {
+	return e.run(ctx, rules, false)
+}
+
+func (e *Engine) run(ctx context.Context, rules []Rule, dryRun bool) *Report {
+	now := e.now()
+	report := &Report{RunID: newRunID(now), DryRun: dryRun, At: now}
+	for _, rule := range rules {
+		plan := e.plan(ctx, report.RunID, now, rule)
+		report.Plans = append(report.Plans, plan)
+		if plan.Err != nil {
+			report.Failed++
+			continue
+		}
+		if plan.Skipped != "" {
+			continue
+		}
+		if !dryRun {
+			if e.apply(ctx, plan); plan.Err != nil {
+				report.Failed++
+				continue
+			}
+		}
+		report.Amount += plan.Amount
+	}
+	return report
+}
+
+// plan 查询余额并计算提现金额
+func (e *Engine) plan(ctx context.Context, runID string, now time.Time, rule Rule) *Plan {
+	acc, policy := rule.Account, rule.Policy
+	plan := &Plan{Account: acc, OutOrderID: OutOrderID(e.prefix, runID, acc)}
+	if policy.Schedule != nil && !policy.Schedule(now.In(beijing)) {
+		plan.Skipped = SkipNotScheduled
+		return plan
+	}
+
+	resp, err := e.withdraw.QueryBalance(ctx, &withdraw.QueryBalanceRequest{
+		ThirdPartyID:   acc.ThirdPartyID,
+		AppID:          acc.AppID,
+		MerchantUID:    acc.MerchantUID,
+		ChannelType:    acc.ChannelType,
+		MerchantEntity: acc.MerchantEntity,
+	})
+	if err != nil {
+		plan.Err = err
+		return plan
+	}
+	if resp.ErrNo != 0 || resp.Data == nil {
+		plan.Err = fmt.Errorf("query balance err_no: %d, err_msg: %s", resp.ErrNo, resp.ErrMsg)
+		return plan
+	}
+
+	plan.Balance = resp.Data.AccountInfo.WithdrawAbleBalance
+	if plan.Balance <= policy.Threshold {
+		plan.Skipped = SkipBelowThreshold
+		return plan
+	}
+	amount := plan.Balance - policy.Reserve
+	if policy.MaxAmount > 0 && amount > policy.MaxAmount {
+		amount = policy.MaxAmount
+	}
+	if amount < 1 || amount < policy.MinAmount {
+		plan.Skipped = SkipBelowMinimum
+		return plan
+	}
+	plan.Amount = amount
+	return plan
+}
+
+// apply 发起提现
+func (e *Engine) apply(ctx context.Context, plan *Plan) {
+	req := &withdraw.MerchantWithdrawRequest{
+		ThirdPartyID:   plan.Account.ThirdPartyID,
+		AppID:          plan.Account.AppID,
+		MerchantUID:    plan.Account.MerchantUID,
+		ChannelType:    plan.Account.ChannelType,
+		WithdrawAmount: plan.Amount,
+		OutOrderID:     plan.OutOrderID,
+		MerchantEntity: plan.Account.MerchantEntity,
+	}
+	if e.tracker != nil {
+		record, err := e.tracker.Apply(ctx, req)
+		if err != nil {
+			plan.Err = err
+			return
+		}
+		plan.Applied, plan.OrderID = true, record.OrderID
+		return
+	}
+
+	resp, err := e.withdraw.Apply(ctx, req)
+	if err != nil {
+		plan.Err = err
+		return
+	}
+	if resp.ErrNo != 0 {
+		plan.Err = fmt.Errorf("withdraw apply err_no: %d, err_msg: %s", resp.ErrNo, resp.ErrMsg)
+		return
+	}
+	plan.Applied = true
+	if resp.Data != nil {
+		plan.OrderID = resp.Data.OrderID
+	}
+}
+
+// OutOrderID 生成提现单号：同一次运行中同一账户的单号相同，不同运行之间不重复
+func OutOrderID(prefix, runID string, acc Account) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%s|%d|%s|%s", acc.MerchantUID, acc.ChannelType, acc.MerchantEntity, acc.AppID, acc.ThirdPartyID)))
+	return prefix + runID + hex.EncodeToString(sum[:])[:8]
+}
+
+// newRunID 生成运行标识：时间戳加随机串
+func newRunID(now time.Time) string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	return now.Format("20060102150405") + hex.EncodeToString(b)
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package autowithdraw
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/houseme/bytedance/pay/withdraw"
+)
+
+func TestEngine(t *testing.T) {
+	balances := map[string]int{
+		"m-1|" + withdraw.Alipay: 10000,
+		"m-1|" + withdraw.Wx:     800,
+		"m-2|" + withdraw.Hz:     50000,
+	}
+	fake := &withdraw.FakeWithdraw{
+		QueryBalanceFunc: func(_ context.Context, req *withdraw.QueryBalanceRequest) (*withdraw.QueryBalanceResponse, error) {
+			return &withdraw.QueryBalanceResponse{Data: &withdraw.QueryBalanceData{
+				AccountInfo: withdraw.AccountInfo{WithdrawAbleBalance: balances[req.MerchantUID+"|"+req.ChannelType]},
+			}}, nil
+		},
+	}
+	monday := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	engine := New(fake, WithClock(func() time.Time { return monday }))
+	rules := []Rule{
+		{Account: Account{MerchantUID: "m-1", ChannelType: withdraw.Alipay}, Policy: Policy{Threshold: 1000, Reserve: 2000, Schedule: BusinessDays}},
+		{Account: Account{MerchantUID: "m-1", ChannelType: withdraw.Wx}, Policy: Policy{Threshold: 1000}},
+		{Account: Account{MerchantUID: "m-2", ChannelType: withdraw.Hz, MerchantEntity: withdraw.MerchantEntityGh}, Policy: Policy{MaxAmount: 30000, Schedule: BusinessDaysExcept(monday)}},
+	}
+
+	dry := engine.DryRun(context.Background(), rules)
+	if !dry.DryRun || dry.Amount != 8000 || fake.Count("Apply") != 0 {
+		t.Fatalf("DryRun() = %+v, apply calls %d", dry, fake.Count("Apply"))
+	}
+	if dry.Plans[1].Skipped != SkipBelowThreshold || dry.Plans[2].Skipped != SkipNotScheduled {
+		t.Errorf("DryRun() skipped = %q, %q", dry.Plans[1].Skipped, dry.Plans[2].Skipped)
+	}
+
+	run := engine.Run(context.Background(), rules[:1])
+	if run.Amount != 8000 || !run.Plans[0].Applied || fake.Count("Apply") != 1 {
+		t.Fatalf("Run() = %+v", run)
+	}
+	req := fake.CallsOf("Apply")[0].Args[0].(*withdraw.MerchantWithdrawRequest)
+	if req.WithdrawAmount != 8000 || req.OutOrderID != run.Plans[0].OutOrderID {
+		t.Errorf("Apply() request = %+v", req)
+	}
+	if run.RunID == dry.RunID || run.Plans[0].OutOrderID == dry.Plans[0].OutOrderID {
+		t.Errorf("runs share out_order_id %s", run.Plans[0].OutOrderID)
+	}
+}
+
+func TestEngineMerchantEntity(t *testing.T) {
+	fake := &withdraw.FakeWithdraw{
+		QueryBalanceFunc: func(_ context.Context, req *withdraw.QueryBalanceRequest) (*withdraw.QueryBalanceResponse, error) {
+			balance := 100
+			if req.MerchantEntity == withdraw.MerchantEntityGh {
+				balance = 5000
+			}
+			return &withdraw.QueryBalanceResponse{Data: &withdraw.QueryBalanceData{
+				AccountInfo: withdraw.AccountInfo{WithdrawAbleBalance: balance},
+			}}, nil
+		},
+	}
+	tracker := withdraw.NewTracker(fake)
+	engine := New(fake, WithTracker(tracker))
+	acc := Account{MerchantUID: "m-1", ChannelType: withdraw.Hz, MerchantEntity: withdraw.MerchantEntityGh, AppID: "app-1"}
+
+	run := engine.Run(context.Background(), []Rule{{Account: acc}})
+	if run.Amount != 5000 || !run.Plans[0].Applied {
+		t.Fatalf("Run() = %+v, err %v", run, run.Plans[0].Err)
+	}
+	for _, call := range fake.CallsOf("QueryBalance") {
+		if req := call.Args[0].(*withdraw.QueryBalanceRequest); req.MerchantEntity != withdraw.MerchantEntityGh {
+			t.Errorf("QueryBalance() entity = %d", req.MerchantEntity)
+		}
+	}
+	if req := fake.CallsOf("Apply")[0].Args[0].(*withdraw.MerchantWithdrawRequest); req.MerchantEntity != withdraw.MerchantEntityGh {
+		t.Errorf("Apply() entity = %d", req.MerchantEntity)
+	}
+
+	other := acc
+	other.AppID = "app-2"
+	if OutOrderID("aw", "run", acc) == OutOrderID("aw", "run", other) {
+		t.Error("OutOrderID() ignores AppID")
+	}
+}
+
+func TestBusinessDaysBeijing(t *testing.T) {
+	var (
+		sundayUTC = time.Date(2026, 10, 18, 20, 0, 0, 0, time.UTC) // 北京时间周一 04:00
+		fridayUTC = time.Date(2026, 10, 16, 17, 0, 0, 0, time.UTC) // 北京时间周六 01:00
+		monday    = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	)
+	if !BusinessDays(sundayUTC) {
+		t.Error("BusinessDays() Beijing Monday = false")
+	}
+	if BusinessDays(fridayUTC) {
+		t.Error("BusinessDays() Beijing Saturday = true")
+	}
+	if BusinessDaysExcept(monday)(sundayUTC) {
+		t.Error("BusinessDaysExcept() Beijing holiday = true")
+	}
+}
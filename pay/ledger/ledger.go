@@ -0,0 +1,342 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+// Package ledger 新交易系统订单台账：统一维护下单、支付、分账、退款的订单状态流转
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/houseme/bytedance/pay/asyncnotify"
+	"github.com/houseme/bytedance/pay/trade"
+)
+
+const (
+	// 查询订单返回的支付状态
+	payStatusSuccess = "SUCCESS"
+	payStatusTimeout = "TIMEOUT"
+	payStatusFailed  = "FAILED"
+
+	// 结果回调状态
+	statusFail = "FAIL"
+
+	// maxSaveAttempts 版本冲突时的最大重试次数
+	maxSaveAttempts = 3
+)
+
+var (
+	// ErrIllegalTransition 非法的状态流转
+	ErrIllegalTransition = errors.New("ledger: illegal state transition")
+	// ErrOrderExists 订单已存在
+	ErrOrderExists = errors.New("ledger: order already exists")
+)
+
+// Option 台账配置项
+type Option func(*options)
+
+type options struct {
+	now func() time.Time
+}
+
+// WithClock 设置时间来源
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// Ledger 订单台账
+type Ledger struct {
+	store Store
+	now   func() time.Time
+}
+
+// New 创建订单台账，store 为 nil 时使用内存存储
+func New(store Store, opts ...Option) *Ledger {
+	op := options{now: time.Now}
+	for _, option := range opts {
+		option(&op)
+	}
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	return &Ledger{store: store, now: op.now}
+}
+
+// Create 按下单请求登记订单，状态为 StateCreated；重复登记返回 ErrOrderExists
+func (l *Ledger) Create(ctx context.Context, req *trade.CreateOrderRequest) (*Order, error) {
+	if req == nil || req.OutOrderNo == "" {
+		return nil, fmt.Errorf("ledger: out_order_no is empty")
+	}
+	if _, err := l.store.Get(ctx, req.OutOrderNo); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrOrderExists, req.OutOrderNo)
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	now := l.now()
+	order := &Order{
+		OutOrderNo:  req.OutOrderNo,
+		Status:      StateCreated,
+		TotalAmount: req.TotalAmount,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := l.store.Save(ctx, order); err != nil {
+		if errors.Is(err, ErrConflict) {
+			return nil, fmt.Errorf("%w: %s", ErrOrderExists, req.OutOrderNo)
+		}
+		return nil, err
+	}
+	return order.clone(), nil
+}
+
+// ApplyPayment 应用支付回调：SUCCESS 流转为已支付，CANCEL 流转为已取消
+func (l *Ledger) ApplyPayment(ctx context.Context, data *asyncnotify.PaymentData) (*Order, error) {
+	if data == nil {
+		return nil, fmt.Errorf("ledger: payment data is empty")
+	}
+	var to State
+	switch data.Status {
+	case asyncnotify.StateSuccess:
+		to = StatePaid
+	case asyncnotify.StateCancel:
+		to = StateCancelled
+	default:
+		return nil, fmt.Errorf("ledger: unknown payment status %q", data.Status)
+	}
+	event := "payment:" + data.Status
+	return l.update(ctx, l.byOutOrderNo(data.OutOrderNo), event, func(o *Order) error {
+		if err := o.transit(to); err != nil {
+			return err
+		}
+		o.OrderID = data.OrderID
+		if data.TotalAmount > 0 {
+			o.TotalAmount = data.TotalAmount
+			o.DiscountAmount = data.DiscountAmount
+		}
+		return nil
+	})
+}
+
+// ApplyQuery 应用查询订单结果，用于回调丢失时补单：SUCCESS 流转为已支付，TIMEOUT/FAILED 流转为已取消，其余状态不处理
+func (l *Ledger) ApplyQuery(ctx context.Context, data *trade.QueryOrderData) (*Order, error) {
+	if data == nil {
+		return nil, fmt.Errorf("ledger: query data is empty")
+	}
+	var to State
+	switch data.PayStatus {
+	case payStatusSuccess:
+		to = StatePaid
+	case payStatusTimeout, payStatusFailed:
+		to = StateCancelled
+	default:
+		return l.store.Get(ctx, data.OutOrderNo)
+	}
+	// 与回调共用事件标识，回调与查询先后到达都只生效一次
+	event := "payment:" + asyncnotify.StateSuccess
+	if to == StateCancelled {
+		event = "payment:" + asyncnotify.StateCancel
+	}
+	return l.update(ctx, l.byOutOrderNo(data.OutOrderNo), event, func(o *Order) error {
+		if err := o.transit(to); err != nil {
+			return err
+		}
+		o.OrderID = data.OrderID
+		o.TotalAmount = data.TotalAmount
+		o.DiscountAmount = data.DiscountAmount
+		return nil
+	})
+}
+
+// MarkSettling 发起分账后标记为分账中
+func (l *Ledger) MarkSettling(ctx context.Context, outOrderNo, outSettleNo string) (*Order, error) {
+	return l.update(ctx, l.byOutOrderNo(outOrderNo), "settle_apply:"+outSettleNo, func(o *Order) error {
+		return o.transit(StateSettling)
+	})
+}
+
+// ApplySettle 应用分账回调：SUCCESS 流转为已分账，FAIL 回到已支付；
+// 分账中发生退款时订单处于退款中，分账结果记为退款结束后回到的状态
+func (l *Ledger) ApplySettle(ctx context.Context, data *asyncnotify.SettleData) (*Order, error) {
+	if data == nil {
+		return nil, fmt.Errorf("ledger: settle data is empty")
+	}
+	var to State
+	switch data.Status {
+	case asyncnotify.StateSuccess:
+		to = StateSettled
+	case statusFail:
+		to = StatePaid
+	default:
+		return nil, fmt.Errorf("ledger: unknown settle status %q", data.Status)
+	}
+	event := "settle:" + data.SettleID + ":" + data.Status
+	return l.update(ctx, l.byOrderID(data.OrderID), event, func(o *Order) error {
+		if o.Status == StateRefunding && o.RefundFrom == StateSettling {
+			o.RefundFrom = to
+		} else if err := o.transit(to); err != nil {
+			return err
+		}
+		if to == StateSettled {
+			o.SettledAmount += data.SettleAmount
+		}
+		return nil
+	})
+}
+
+// MarkRefunding 发起退款后标记为退款中，同一订单可同时有多笔处理中的退款
+func (l *Ledger) MarkRefunding(ctx context.Context, outOrderNo, outRefundNo string) (*Order, error) {
+	return l.update(ctx, l.byOutOrderNo(outOrderNo), "refund_apply:"+outRefundNo, func(o *Order) error {
+		if err := o.enterRefunding(); err != nil {
+			return err
+		}
+		o.PendingRefunds = append(o.PendingRefunds, outRefundNo)
+		return nil
+	})
+}
+
+// ApplyRefund 应用退款回调：累计退款达到实付金额时流转为已退款；
+// 未退满时待处理中的退款全部有结果后回到退款前的状态。
+// 未经 MarkRefunding 的退款（如平台或客服发起）在已支付或已分账状态下同样生效
+func (l *Ledger) ApplyRefund(ctx context.Context, data *asyncnotify.RefundData) (*Order, error) {
+	if data == nil {
+		return nil, fmt.Errorf("ledger: refund data is empty")
+	}
+	if data.Status != asyncnotify.StateSuccess && data.Status != statusFail {
+		return nil, fmt.Errorf("ledger: unknown refund status %q", data.Status)
+	}
+	event := "refund:" + data.RefundID + ":" + data.Status
+	return l.update(ctx, l.byOrderID(data.OrderID), event, func(o *Order) error {
+		if err := o.enterRefunding(); err != nil {
+			return err
+		}
+		o.PendingRefunds = removeString(o.PendingRefunds, data.OutRefundNo)
+		if data.Status == asyncnotify.StateSuccess {
+			o.RefundedAmount += data.RefundTotalAmount
+		}
+		switch {
+		case o.RefundedAmount >= o.PaidAmount():
+			o.PendingRefunds = nil
+			if err := o.transit(StateRefunded); err != nil {
+				return err
+			}
+		case len(o.PendingRefunds) == 0:
+			if err := o.transit(o.RefundFrom); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+		o.RefundFrom = ""
+		return nil
+	})
+}
+
+// enterRefunding 流转为退款中，仅在离开非退款中状态时记录退款前的状态
+func (o *Order) enterRefunding() error {
+	if o.Status == StateRefunding {
+		return nil
+	}
+	from := o.Status
+	if err := o.transit(StateRefunding); err != nil {
+		return err
+	}
+	o.RefundFrom = from
+	return nil
+}
+
+// removeString 删除 list 中的 s
+func removeString(list []string, s string) []string {
+	for i, v := range list {
+		if v == s {
+			return append(list[:i:i], list[i+1:]...)
+		}
+	}
+	return list
+}
+
+// Get 按开发者订单号查询
+func (l *Ledger) Get(ctx context.Context, outOrderNo string) (*Order, error) {
+	return l.store.Get(ctx, outOrderNo)
+}
+
+// GetByOrderID 按平台订单号查询
+func (l *Ledger) GetByOrderID(ctx context.Context, orderID string) (*Order, error) {
+	return l.store.GetByOrderID(ctx, orderID)
+}
+
+// ListByStatus 按状态查询
+func (l *Ledger) ListByStatus(ctx context.Context, status State) ([]*Order, error) {
+	return l.store.ListByStatus(ctx, status)
+}
+
+type loader func(ctx context.Context) (*Order, error)
+
+func (l *Ledger) byOutOrderNo(outOrderNo string) loader {
+	return func(ctx context.Context) (*Order, error) {
+		return l.store.Get(ctx, outOrderNo)
+	}
+}
+
+func (l *Ledger) byOrderID(orderID string) loader {
+	return func(ctx context.Context) (*Order, error) {
+		return l.store.GetByOrderID(ctx, orderID)
+	}
+}
+
+// update 读取订单并应用事件，事件已应用过时直接返回当前订单；版本冲突时重新读取后重试
+func (l *Ledger) update(ctx context.Context, load loader, event string, apply func(*Order) error) (*Order, error) {
+	var err error
+	for i := 0; i < maxSaveAttempts; i++ {
+		var order *Order
+		if order, err = load(ctx); err != nil {
+			return nil, err
+		}
+		if order.applied(event) {
+			return order, nil
+		}
+		if err = apply(order); err != nil {
+			return nil, fmt.Errorf("%w (order %s)", err, order.OutOrderNo)
+		}
+		order.Applied = append(order.Applied, event)
+		order.UpdatedAt = l.now()
+		if err = l.store.Save(ctx, order); err == nil {
+			return order.clone(), nil
+		}
+		if !errors.Is(err, ErrConflict) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// transit 流转到 to，已处于 to 时视为幂等
+func (o *Order) transit(to State) error {
+	if o.Status == to {
+		return nil
+	}
+	if !CanTransition(o.Status, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrIllegalTransition, o.Status, to)
+	}
+	o.Status = to
+	return nil
+}
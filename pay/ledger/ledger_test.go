@@ -0,0 +1,192 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package ledger
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/houseme/bytedance/pay/asyncnotify"
+	"github.com/houseme/bytedance/pay/trade"
+)
+
+func TestLedgerLifecycle(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	l := New(nil, WithClock(func() time.Time { return now }))
+
+	if _, err := l.Create(ctx, &trade.CreateOrderRequest{OutOrderNo: "o1", TotalAmount: 1000}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := l.Create(ctx, &trade.CreateOrderRequest{OutOrderNo: "o1", TotalAmount: 1000}); !errors.Is(err, ErrOrderExists) {
+		t.Fatalf("duplicate create: %v", err)
+	}
+
+	// 查询结果先于回调到达，回调重复投递均只生效一次
+	if _, err := l.ApplyQuery(ctx, &trade.QueryOrderData{OutOrderNo: "o1", OrderID: "d1", PayStatus: "SUCCESS", TotalAmount: 1000, DiscountAmount: 100}); err != nil {
+		t.Fatalf("apply query: %v", err)
+	}
+	pay := &asyncnotify.PaymentData{OutOrderNo: "o1", OrderID: "d1", Status: asyncnotify.StateSuccess, TotalAmount: 1000, DiscountAmount: 100}
+	for i := 0; i < 2; i++ {
+		o, err := l.ApplyPayment(ctx, pay)
+		if err != nil || o.Status != StatePaid || o.Version != 2 {
+			t.Fatalf("apply payment #%d: %+v, %v", i, o, err)
+		}
+	}
+	if _, err := l.ApplyPayment(ctx, &asyncnotify.PaymentData{OutOrderNo: "o1", Status: asyncnotify.StateCancel}); !errors.Is(err, ErrIllegalTransition) {
+		t.Fatalf("cancel after paid: %v", err)
+	}
+
+	// 分账失败回到已支付，再次分账成功
+	if _, err := l.MarkSettling(ctx, "o1", "s1"); err != nil {
+		t.Fatalf("mark settling: %v", err)
+	}
+	if o, _ := l.ApplySettle(ctx, &asyncnotify.SettleData{OrderID: "d1", SettleID: "x1", Status: "FAIL"}); o.Status != StatePaid {
+		t.Fatalf("settle fail: %+v", o)
+	}
+	if _, err := l.MarkSettling(ctx, "o1", "s2"); err != nil {
+		t.Fatalf("mark settling again: %v", err)
+	}
+	if o, _ := l.ApplySettle(ctx, &asyncnotify.SettleData{OrderID: "d1", SettleID: "x2", Status: "SUCCESS", SettleAmount: 900}); o.Status != StateSettled || o.SettledAmount != 900 {
+		t.Fatalf("settle success: %+v", o)
+	}
+
+	// 部分退款回到已分账，累计退满实付金额后为已退款
+	if _, err := l.MarkRefunding(ctx, "o1", "r1"); err != nil {
+		t.Fatalf("mark refunding: %v", err)
+	}
+	refund := &asyncnotify.RefundData{OrderID: "d1", RefundID: "f1", OutRefundNo: "r1", Status: "SUCCESS", RefundTotalAmount: 400}
+	if o, _ := l.ApplyRefund(ctx, refund); o.Status != StateSettled || o.RefundedAmount != 400 {
+		t.Fatalf("partial refund: %+v", o)
+	}
+	if o, _ := l.ApplyRefund(ctx, refund); o.RefundedAmount != 400 {
+		t.Fatalf("duplicate refund callback: %+v", o)
+	}
+	if _, err := l.MarkRefunding(ctx, "o1", "r2"); err != nil {
+		t.Fatalf("mark refunding again: %v", err)
+	}
+	if o, _ := l.ApplyRefund(ctx, &asyncnotify.RefundData{OrderID: "d1", RefundID: "f2", OutRefundNo: "r2", Status: "SUCCESS", RefundTotalAmount: 500}); o.Status != StateRefunded || !o.Status.IsTerminal() {
+		t.Fatalf("full refund: %+v", o)
+	}
+
+	list, err := l.ListByStatus(ctx, StateRefunded)
+	if err != nil || len(list) != 1 || list[0].OutOrderNo != "o1" {
+		t.Fatalf("list: %+v, %v", list, err)
+	}
+	if _, err := l.GetByOrderID(ctx, "unknown"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("get unknown: %v", err)
+	}
+}
+
+func TestLedgerOverlappingRefunds(t *testing.T) {
+	ctx := context.Background()
+	l := New(nil)
+	paid(t, l, "o1", "d1", 300)
+
+	for _, no := range []string{"r1", "r2"} {
+		if _, err := l.MarkRefunding(ctx, "o1", no); err != nil {
+			t.Fatalf("mark refunding %s: %v", no, err)
+		}
+	}
+	o, err := l.ApplyRefund(ctx, &asyncnotify.RefundData{OrderID: "d1", RefundID: "f1", OutRefundNo: "r1", Status: "SUCCESS", RefundTotalAmount: 100})
+	if err != nil || o.Status != StateRefunding || o.RefundFrom != StatePaid || len(o.PendingRefunds) != 1 {
+		t.Fatalf("first result: %+v, %v", o, err)
+	}
+	o, err = l.ApplyRefund(ctx, &asyncnotify.RefundData{OrderID: "d1", RefundID: "f2", OutRefundNo: "r2", Status: "FAIL"})
+	if err != nil || o.Status != StatePaid || o.RefundFrom != "" || o.RefundedAmount != 100 {
+		t.Fatalf("second result: %+v, %v", o, err)
+	}
+}
+
+func TestLedgerUnmarkedRefund(t *testing.T) {
+	ctx := context.Background()
+	l := New(nil)
+	paid(t, l, "o1", "d1", 300)
+
+	// 平台或客服发起的退款没有 MarkRefunding
+	o, err := l.ApplyRefund(ctx, &asyncnotify.RefundData{OrderID: "d1", RefundID: "f1", OutRefundNo: "cs1", Status: "SUCCESS", RefundTotalAmount: 100})
+	if err != nil || o.Status != StatePaid || o.RefundedAmount != 100 {
+		t.Fatalf("partial unmarked refund: %+v, %v", o, err)
+	}
+	o, err = l.ApplyRefund(ctx, &asyncnotify.RefundData{OrderID: "d1", RefundID: "f2", OutRefundNo: "cs2", Status: "SUCCESS", RefundTotalAmount: 200})
+	if err != nil || o.Status != StateRefunded {
+		t.Fatalf("full unmarked refund: %+v, %v", o, err)
+	}
+}
+
+func TestLedgerRefundWhileSettling(t *testing.T) {
+	ctx := context.Background()
+	l := New(nil)
+	paid(t, l, "o1", "d1", 300)
+	if _, err := l.MarkSettling(ctx, "o1", "s1"); err != nil {
+		t.Fatalf("mark settling: %v", err)
+	}
+
+	// 分账中收到退款回调，退款被记录，部分退款后回到分账中
+	o, err := l.ApplyRefund(ctx, &asyncnotify.RefundData{OrderID: "d1", RefundID: "f1", OutRefundNo: "cs1", Status: "SUCCESS", RefundTotalAmount: 100})
+	if err != nil || o.Status != StateSettling || o.RefundedAmount != 100 {
+		t.Fatalf("refund while settling: %+v, %v", o, err)
+	}
+
+	// 退款处理中收到分账结果，退款结束后回到已分账
+	if _, err = l.MarkRefunding(ctx, "o1", "r2"); err != nil {
+		t.Fatalf("mark refunding: %v", err)
+	}
+	o, err = l.ApplySettle(ctx, &asyncnotify.SettleData{OrderID: "d1", SettleID: "st1", Status: "SUCCESS", SettleAmount: 200})
+	if err != nil || o.Status != StateRefunding || o.RefundFrom != StateSettled || o.SettledAmount != 200 {
+		t.Fatalf("settle while refunding: %+v, %v", o, err)
+	}
+	o, err = l.ApplyRefund(ctx, &asyncnotify.RefundData{OrderID: "d1", RefundID: "f2", OutRefundNo: "r2", Status: "FAIL"})
+	if err != nil || o.Status != StateSettled {
+		t.Fatalf("refund failed after settle: %+v, %v", o, err)
+	}
+}
+
+// paid 登记并支付一笔订单
+func paid(t *testing.T, l *Ledger, outOrderNo, orderID string, amount int) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := l.Create(ctx, &trade.CreateOrderRequest{OutOrderNo: outOrderNo, TotalAmount: amount}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := l.ApplyPayment(ctx, &asyncnotify.PaymentData{OutOrderNo: outOrderNo, OrderID: orderID, Status: asyncnotify.StateSuccess, TotalAmount: amount}); err != nil {
+		t.Fatalf("apply payment: %v", err)
+	}
+}
+
+func TestMemoryStoreConflict(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	if err := s.Save(ctx, &Order{OutOrderNo: "o1", Status: StateCreated}); err != nil {
+		t.Fatal(err)
+	}
+	a, _ := s.Get(ctx, "o1")
+	b, _ := s.Get(ctx, "o1")
+	a.Status = StatePaid
+	if err := s.Save(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+	b.Status = StateCancelled
+	if err := s.Save(ctx, b); !errors.Is(err, ErrConflict) {
+		t.Fatalf("stale save: %v", err)
+	}
+}
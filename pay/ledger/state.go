@@ -0,0 +1,65 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package ledger
+
+// State 订单状态
+type State string
+
+const (
+	// StateCreated 已下单，待支付
+	StateCreated State = "CREATED"
+	// StatePaid 已支付
+	StatePaid State = "PAID"
+	// StateCancelled 已取消，支付取消或超时
+	StateCancelled State = "CANCELLED"
+	// StateSettling 分账中
+	StateSettling State = "SETTLING"
+	// StateSettled 已分账
+	StateSettled State = "SETTLED"
+	// StateRefunding 退款中
+	StateRefunding State = "REFUNDING"
+	// StateRefunded 已全额退款
+	StateRefunded State = "REFUNDED"
+)
+
+// transitions 允许的状态流转；分账失败回到已支付，退款失败或部分退款回到退款前的状态，
+// 分账中也可以发生退款，退款期间收到的分账结果记录在 RefundFrom 上
+var transitions = map[State][]State{
+	StateCreated:   {StatePaid, StateCancelled},
+	StatePaid:      {StateSettling, StateSettled, StateRefunding},
+	StateSettling:  {StateSettled, StatePaid, StateRefunding},
+	StateSettled:   {StateRefunding},
+	StateRefunding: {StateRefunded, StatePaid, StateSettling, StateSettled},
+}
+
+// CanTransition 是否允许从 from 流转到 to
+func CanTransition(from, to State) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal 是否为终态
+func (s State) IsTerminal() bool {
+	return len(transitions[s]) == 0
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package ledger
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound 订单不存在
+	ErrNotFound = errors.New("ledger: order not found")
+	// ErrConflict 并发更新冲突，订单版本已变化
+	ErrConflict = errors.New("ledger: version conflict")
+)
+
+// Order 订单台账
+type Order struct {
+	OutOrderNo     string
+	OrderID        string
+	Status         State
+	TotalAmount    int
+	DiscountAmount int
+	SettledAmount  int
+	RefundedAmount int
+	RefundFrom     State    // 退款前的状态，处理中的退款全部有结果且未退满时回到该状态
+	PendingRefunds []string // 处理中的退款单号，为空时离开退款中
+	Applied        []string // 已应用的事件标识，用于幂等；不做裁剪，随订单的回调次数增长，一笔订单通常只有数十条
+	Version        int      // 每次保存加一，用于乐观锁
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// PaidAmount 实付金额
+func (o *Order) PaidAmount() int {
+	return o.TotalAmount - o.DiscountAmount
+}
+
+// clone 深拷贝
+func (o *Order) clone() *Order {
+	c := *o
+	c.Applied = append([]string(nil), o.Applied...)
+	c.PendingRefunds = append([]string(nil), o.PendingRefunds...)
+	return &c
+}
+
+// applied 事件是否已应用
+func (o *Order) applied(event string) bool {
+	for _, e := range o.Applied {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Store 台账存储
+type Store interface {
+	// Get 按开发者订单号查询，不存在时返回 ErrNotFound
+	Get(ctx context.Context, outOrderNo string) (*Order, error)
+	// GetByOrderID 按平台订单号查询，不存在时返回 ErrNotFound
+	GetByOrderID(ctx context.Context, orderID string) (*Order, error)
+	// ListByStatus 按状态查询
+	ListByStatus(ctx context.Context, status State) ([]*Order, error)
+	// Save 保存订单，order.Version 需等于已保存的版本（新订单为 0），否则返回 ErrConflict；保存成功后版本加一
+	Save(ctx context.Context, order *Order) error
+}
+
+// MemoryStore 内存存储
+type MemoryStore struct {
+	mu      sync.RWMutex
+	orders  map[string]*Order
+	orderID map[string]string
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		orders:  make(map[string]*Order),
+		orderID: make(map[string]string),
+	}
+}
+
+// Get implements Store
+func (m *MemoryStore) Get(_ context.Context, outOrderNo string) (*Order, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	o, ok := m.orders[outOrderNo]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return o.clone(), nil
+}
+
+// GetByOrderID implements Store
+func (m *MemoryStore) GetByOrderID(ctx context.Context, orderID string) (*Order, error) {
+	m.mu.RLock()
+	outOrderNo, ok := m.orderID[orderID]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return m.Get(ctx, outOrderNo)
+}
+
+// ListByStatus implements Store
+func (m *MemoryStore) ListByStatus(_ context.Context, status State) ([]*Order, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var list []*Order
+	for _, o := range m.orders {
+		if o.Status == status {
+			list = append(list, o.clone())
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].OutOrderNo < list[j].OutOrderNo
+	})
+	return list, nil
+}
+
+// Save implements Store
+func (m *MemoryStore) Save(_ context.Context, order *Order) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var version int
+	if o, ok := m.orders[order.OutOrderNo]; ok {
+		version = o.Version
+	}
+	if order.Version != version {
+		return ErrConflict
+	}
+	order.Version++
+	m.orders[order.OutOrderNo] = order.clone()
+	if order.OrderID != "" {
+		m.orderID[order.OrderID] = order.OutOrderNo
+	}
+	return nil
+}
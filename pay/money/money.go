@@ -0,0 +1,139 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+// Package money 金额，以分为单位的整数运算，避免浮点误差与溢出
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrOverflow 金额运算溢出
+	ErrOverflow = errors.New("money: overflow")
+	// ErrInvalidAmount 金额格式不合法
+	ErrInvalidAmount = errors.New("money: invalid amount")
+)
+
+// Money 金额，单位分
+type Money int64
+
+// FromCents 以分为单位构造金额
+func FromCents(cents int) Money {
+	return Money(cents)
+}
+
+// ParseYuan 解析以元为单位的金额，最多两位小数，如 "12.34"
+func ParseYuan(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	yuan, fen, hasFen := strings.Cut(s, ".")
+	if yuan == "" || (hasFen && (fen == "" || len(fen) > 2)) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	for len(fen) < 2 {
+		fen += "0"
+	}
+	y, err := strconv.ParseInt(yuan, 10, 64)
+	if err != nil || y < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	f, err := strconv.ParseInt(fen, 10, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	m, err := Money(y).Mul(100)
+	if err != nil {
+		return 0, err
+	}
+	if m, err = m.Add(Money(f)); err != nil {
+		return 0, err
+	}
+	if neg {
+		m = -m
+	}
+	return m, nil
+}
+
+// Sum 金额求和
+func Sum(ms ...Money) (total Money, err error) {
+	for _, m := range ms {
+		if total, err = total.Add(m); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// Cents 以分为单位的整数值，超出 int 范围时返回 ErrOverflow
+func (m Money) Cents() (int, error) {
+	if int64(m) > math.MaxInt || int64(m) < math.MinInt {
+		return 0, ErrOverflow
+	}
+	return int(m), nil
+}
+
+// Add 加法
+func (m Money) Add(o Money) (Money, error) {
+	if (o > 0 && m > math.MaxInt64-o) || (o < 0 && m < math.MinInt64-o) {
+		return 0, ErrOverflow
+	}
+	return m + o, nil
+}
+
+// Sub 减法
+func (m Money) Sub(o Money) (Money, error) {
+	if (o < 0 && m > math.MaxInt64+o) || (o > 0 && m < math.MinInt64+o) {
+		return 0, ErrOverflow
+	}
+	return m - o, nil
+}
+
+// Mul 乘以数量
+func (m Money) Mul(n int) (Money, error) {
+	if m == 0 || n == 0 {
+		return 0, nil
+	}
+	r := m * Money(n)
+	if r/Money(n) != m || (m == -1 && int64(n) == math.MinInt64) || (int64(n) == -1 && int64(m) == math.MinInt64) {
+		return 0, ErrOverflow
+	}
+	return r, nil
+}
+
+// IsPositive 是否大于 0
+func (m Money) IsPositive() bool {
+	return m > 0
+}
+
+// String 以元为单位格式化，如 12.34
+func (m Money) String() string {
+	sign := ""
+	v := uint64(m)
+	if m < 0 {
+		sign = "-"
+		v = uint64(-(m + 1)) + 1
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/100, v%100)
+}
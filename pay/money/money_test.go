@@ -0,0 +1,58 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package money
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMoney(t *testing.T) {
+	for in, want := range map[string]Money{"12.34": 1234, "0.5": 50, "7": 700, "-1.05": -105} {
+		if got, err := ParseYuan(in); err != nil || got != want {
+			t.Errorf("ParseYuan(%q) = %d, %v, want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "1.234", "abc", "1.", ".5"} {
+		if _, err := ParseYuan(in); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("ParseYuan(%q) err = %v, want ErrInvalidAmount", in, err)
+		}
+	}
+
+	if got := Money(-105).String(); got != "-1.05" {
+		t.Errorf("String() = %q, want -1.05", got)
+	}
+	if got, err := Money(199).Mul(3); err != nil || got != 597 {
+		t.Errorf("Mul() = %d, %v, want 597", got, err)
+	}
+	if _, err := Money(math.MaxInt64).Add(1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Add() overflow err = %v", err)
+	}
+	if _, err := Money(math.MinInt64).Sub(1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Sub() overflow err = %v", err)
+	}
+	if _, err := Money(math.MaxInt64 / 2).Mul(3); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Mul() overflow err = %v", err)
+	}
+	if got, err := Sum(100, 250, -50); err != nil || got != 300 {
+		t.Errorf("Sum() = %d, %v, want 300", got, err)
+	}
+}
@@ -80,26 +80,26 @@ func (p *Pay) ContextConfig() *credential.ContextConfig {
 }
 
 // Trade payment trade relation
-func (p *Pay) Trade() *trade.Trade {
+func (p *Pay) Trade() trade.ITrade {
 	return trade.NewTrade(p.ContextConfig())
 }
 
 // Withdraw cash
-func (p *Pay) Withdraw() *withdraw.Withdraw {
+func (p *Pay) Withdraw() withdraw.IWithdraw {
 	return withdraw.NewWithdraw(p.ContextConfig())
 }
 
 // Settle account cash
-func (p *Pay) Settle() *settle.Settle {
+func (p *Pay) Settle() settle.ISettle {
 	return settle.NewSettle(p.ContextConfig())
 }
 
 // Refund order cash
-func (p *Pay) Refund() *refund.Refund {
+func (p *Pay) Refund() refund.IRefund {
 	return refund.NewRefund(p.ContextConfig())
 }
 
 // AsyncNotify async
-func (p *Pay) AsyncNotify() *asyncnotify.AsyncNotify {
+func (p *Pay) AsyncNotify() asyncnotify.IAsyncNotify {
 	return asyncnotify.NewAsyncNotify(p.ContextConfig())
 }
@@ -0,0 +1,303 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+// Package reconcile 交易对账：按本地订单查询平台订单与分账状态，输出差异并可回调修复本地状态
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/houseme/bytedance/pay/settle"
+	"github.com/houseme/bytedance/pay/trade"
+)
+
+// Status 订单状态
+type Status string
+
+const (
+	// StatusPending 待支付或支付处理中
+	StatusPending Status = "PENDING"
+	// StatusPaid 已支付
+	StatusPaid Status = "PAID"
+	// StatusCancelled 已取消，超时未支付
+	StatusCancelled Status = "CANCELLED"
+	// StatusSettled 已分账
+	StatusSettled Status = "SETTLED"
+)
+
+// Kind 差异类型
+type Kind string
+
+const (
+	// KindMissing 平台不存在该订单，仅在通过 WithNotExistErrNo 设置了订单不存在的错误码时产生
+	KindMissing Kind = "MISSING"
+	// KindAmountMismatch 实付金额不一致
+	KindAmountMismatch Kind = "AMOUNT_MISMATCH"
+	// KindStatusMismatch 状态不一致
+	KindStatusMismatch Kind = "STATUS_MISMATCH"
+	// KindQueryFailed 查询失败，无法判断
+	KindQueryFailed Kind = "QUERY_FAILED"
+)
+
+const (
+	// defaultInterval 默认查询间隔，即每秒 10 次
+	defaultInterval = 100 * time.Millisecond
+
+	payStatusSuccess = "SUCCESS"
+	payStatusTimeout = "TIMEOUT"
+)
+
+// LocalOrder 本地订单
+type LocalOrder struct {
+	OutOrderNo string
+	Status     Status
+	Amount     int // 实付金额，单位分，即 total_amount - discount_amount
+}
+
+// Discrepancy 对账差异
+type Discrepancy struct {
+	Kind         Kind
+	Local        LocalOrder
+	RemoteStatus Status
+	Remote       *trade.QueryOrderData
+	Settles      []*settle.QuerySettleData
+	Err          error // KindQueryFailed 时的查询错误
+	Repaired     bool
+	RepairErr    error
+}
+
+// Report 对账报告
+type Report struct {
+	Checked       int
+	Matched       int
+	Repaired      int
+	Discrepancies []*Discrepancy
+}
+
+// RepairFunc 修复本地状态的回调，返回 nil 表示已修复
+type RepairFunc func(ctx context.Context, d *Discrepancy) error
+
+// Option 对账配置项
+type Option func(*options)
+
+type options struct {
+	settle   settle.ISettle
+	repair   RepairFunc
+	interval time.Duration
+	notExist []int
+}
+
+// WithSettle 同时查询分账状态，本地为已分账或平台已分账时才能比对
+func WithSettle(s settle.ISettle) Option {
+	return func(o *options) {
+		o.settle = s
+	}
+}
+
+// WithRepair 设置修复回调，查询失败以外的差异都会回调
+func WithRepair(fn RepairFunc) Option {
+	return func(o *options) {
+		o.repair = fn
+	}
+}
+
+// WithInterval 设置两次查询之间的最小间隔，用于限流，0 表示不限流
+func WithInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.interval = d
+	}
+}
+
+// WithNotExistErrNo 设置查询订单时表示订单不存在的 err_no，命中时记为 KindMissing；
+// 未设置时所有非 0 的 err_no 都记为 KindQueryFailed
+func WithNotExistErrNo(errNo ...int) Option {
+	return func(o *options) {
+		o.notExist = append(o.notExist, errNo...)
+	}
+}
+
+// Reconciler 对账，可并发调用 Run，限流间隔在所有调用之间共享
+type Reconciler struct {
+	trade    trade.ITrade
+	settle   settle.ISettle
+	repair   RepairFunc
+	interval time.Duration
+	notExist []int
+
+	mu   sync.Mutex
+	last time.Time // 最近一次已分配的查询时间
+}
+
+// New 创建对账，t 通常为 pay.Pay.Trade()
+func New(t trade.ITrade, opts ...Option) *Reconciler {
+	op := options{interval: defaultInterval}
+	for _, option := range opts {
+		option(&op)
+	}
+	return &Reconciler{
+		trade:    t,
+		settle:   op.settle,
+		repair:   op.repair,
+		interval: op.interval,
+		notExist: op.notExist,
+	}
+}
+
+// Run 逐笔对账，ctx 取消时返回已完成部分的报告与 ctx 的错误
+func (r *Reconciler) Run(ctx context.Context, orders []LocalOrder) (*Report, error) {
+	report := &Report{}
+	for _, local := range orders {
+		d, err := r.check(ctx, local)
+		if err != nil {
+			return report, err
+		}
+		report.Checked++
+		if d == nil {
+			report.Matched++
+			continue
+		}
+		if d.Kind != KindQueryFailed && r.repair != nil {
+			if d.RepairErr = r.repair(ctx, d); d.RepairErr == nil {
+				d.Repaired = true
+				report.Repaired++
+			}
+		}
+		report.Discrepancies = append(report.Discrepancies, d)
+	}
+	return report, nil
+}
+
+// check 对账单笔订单，一致时返回 nil
+func (r *Reconciler) check(ctx context.Context, local LocalOrder) (*Discrepancy, error) {
+	if err := r.wait(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := r.trade.QueryTrade(ctx, &trade.QueryOrderRequest{OutOrderNo: local.OutOrderNo})
+	switch {
+	case err != nil:
+		return &Discrepancy{Kind: KindQueryFailed, Local: local, Err: err}, nil
+	case resp == nil:
+		return &Discrepancy{Kind: KindQueryFailed, Local: local, Err: errors.New("query trade: empty response")}, nil
+	case r.isNotExist(resp.ErrNo):
+		return &Discrepancy{Kind: KindMissing, Local: local}, nil
+	case resp.ErrNo != 0 || resp.Data == nil:
+		return &Discrepancy{Kind: KindQueryFailed, Local: local, Err: fmt.Errorf("query trade: %d %s", resp.ErrNo, resp.ErrMsg)}, nil
+	}
+
+	d := &Discrepancy{Local: local, Remote: resp.Data, RemoteStatus: payStatus(resp.Data.PayStatus)}
+	if d.RemoteStatus == StatusPaid && r.settle != nil {
+		if err = r.wait(ctx); err != nil {
+			return nil, err
+		}
+		var sr *settle.QuerySettleResponse
+		sr, err = r.settle.Query(ctx, &settle.QuerySettleRequest{OutOrderNo: local.OutOrderNo})
+		switch {
+		case err == nil && sr == nil:
+			err = errors.New("query settle: empty response")
+		case err == nil && sr.ErrNo != 0:
+			err = fmt.Errorf("query settle: %d %s", sr.ErrNo, sr.ErrMsg)
+		}
+		if err != nil {
+			d.Kind, d.Err = KindQueryFailed, err
+			return d, nil
+		}
+		d.Settles = sr.Data
+		if settled(sr.Data) {
+			d.RemoteStatus = StatusSettled
+		}
+	}
+
+	switch {
+	case d.RemoteStatus != local.Status:
+		d.Kind = KindStatusMismatch
+	case resp.Data.TotalAmount-resp.Data.DiscountAmount != local.Amount:
+		d.Kind = KindAmountMismatch
+	default:
+		return nil, nil
+	}
+	return d, nil
+}
+
+// isNotExist err_no 是否表示订单不存在
+func (r *Reconciler) isNotExist(errNo int) bool {
+	for _, n := range r.notExist {
+		if errNo != 0 && errNo == n {
+			return true
+		}
+	}
+	return false
+}
+
+// wait 限流，在上次分配的查询时间之后 interval 处分配本次查询时间并等待到该时间
+func (r *Reconciler) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	now := time.Now()
+	at := now
+	if r.interval > 0 && !r.last.IsZero() {
+		if next := r.last.Add(r.interval); next.After(at) {
+			at = next
+		}
+	}
+	r.last = at
+	r.mu.Unlock()
+
+	if d := at.Sub(now); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
+// payStatus 平台支付状态转为对账状态，SUCCESS 为已支付，TIMEOUT 为已取消；
+// 支付处理中（PROCESS）及其他未列出的值均视为待支付
+func payStatus(s string) Status {
+	switch s {
+	case payStatusSuccess:
+		return StatusPaid
+	case payStatusTimeout:
+		return StatusCancelled
+	default:
+		return StatusPending
+	}
+}
+
+// settled 分账单均已成功
+func settled(list []*settle.QuerySettleData) bool {
+	if len(list) == 0 {
+		return false
+	}
+	for _, s := range list {
+		if s.SettleStatus != settle.StateSuccess {
+			return false
+		}
+	}
+	return true
+}
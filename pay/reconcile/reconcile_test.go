@@ -0,0 +1,149 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/houseme/bytedance/pay/settle"
+	"github.com/houseme/bytedance/pay/trade"
+)
+
+// errNoNotExist 测试用的订单不存在错误码
+const errNoNotExist = 1004
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	remote := map[string]*trade.QueryOrderData{
+		"paid":     {OutOrderNo: "paid", PayStatus: "SUCCESS", TotalAmount: 1000, DiscountAmount: 100},
+		"lost":     {OutOrderNo: "lost", PayStatus: "SUCCESS", TotalAmount: 500},
+		"amount":   {OutOrderNo: "amount", PayStatus: "SUCCESS", TotalAmount: 800},
+		"settled":  {OutOrderNo: "settled", PayStatus: "SUCCESS", TotalAmount: 300},
+		"timeout":  {OutOrderNo: "timeout", PayStatus: "TIMEOUT", TotalAmount: 300},
+		"unstable": {OutOrderNo: "unstable"},
+	}
+	ft := &trade.FakeTrade{
+		QueryTradeFunc: func(_ context.Context, req *trade.QueryOrderRequest) (*trade.QueryOrderResponse, error) {
+			if req.OutOrderNo == "unstable" {
+				return nil, errors.New("network down")
+			}
+			if d, ok := remote[req.OutOrderNo]; ok {
+				return &trade.QueryOrderResponse{Data: d}, nil
+			}
+			return &trade.QueryOrderResponse{ErrNo: errNoNotExist, ErrMsg: "order not exist"}, nil
+		},
+	}
+	fs := &settle.FakeSettle{
+		QueryFunc: func(_ context.Context, req *settle.QuerySettleRequest) (*settle.QuerySettleResponse, error) {
+			if req.OutOrderNo == "settled" {
+				return &settle.QuerySettleResponse{Data: []*settle.QuerySettleData{{SettleStatus: settle.StateSuccess}}}, nil
+			}
+			return &settle.QuerySettleResponse{}, nil
+		},
+	}
+	var repaired []string
+	r := New(ft, WithSettle(fs), WithInterval(time.Millisecond), WithNotExistErrNo(errNoNotExist), WithRepair(func(_ context.Context, d *Discrepancy) error {
+		if d.Kind != KindStatusMismatch {
+			return errors.New("manual check")
+		}
+		repaired = append(repaired, d.Local.OutOrderNo)
+		return nil
+	}))
+
+	report, err := r.Run(ctx, []LocalOrder{
+		{OutOrderNo: "paid", Status: StatusPaid, Amount: 900},
+		{OutOrderNo: "lost", Status: StatusPending, Amount: 500},
+		{OutOrderNo: "amount", Status: StatusPaid, Amount: 900},
+		{OutOrderNo: "settled", Status: StatusPaid, Amount: 300},
+		{OutOrderNo: "timeout", Status: StatusCancelled, Amount: 300},
+		{OutOrderNo: "ghost", Status: StatusPaid, Amount: 100},
+		{OutOrderNo: "unstable", Status: StatusPaid, Amount: 100},
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if report.Checked != 7 || report.Matched != 2 || report.Repaired != 2 || len(report.Discrepancies) != 5 {
+		t.Fatalf("report: %+v", report)
+	}
+	want := []Kind{KindStatusMismatch, KindAmountMismatch, KindStatusMismatch, KindMissing, KindQueryFailed}
+	for i, d := range report.Discrepancies {
+		if d.Kind != want[i] {
+			t.Fatalf("discrepancy %d: got %s, want %s", i, d.Kind, want[i])
+		}
+	}
+	if d := report.Discrepancies[2]; d.RemoteStatus != StatusSettled || !d.Repaired {
+		t.Fatalf("settled: %+v", d)
+	}
+	if d := report.Discrepancies[4]; d.Repaired || d.Err == nil {
+		t.Fatalf("query failed must not be repaired: %+v", d)
+	}
+	if len(repaired) != 2 || repaired[0] != "lost" {
+		t.Fatalf("repaired: %v", repaired)
+	}
+
+	report, _ = New(ft).Run(ctx, []LocalOrder{{OutOrderNo: "ghost", Status: StatusPaid}})
+	if len(report.Discrepancies) != 1 || report.Discrepancies[0].Kind != KindQueryFailed {
+		t.Fatalf("unknown err_no must be a query failure: %+v", report.Discrepancies)
+	}
+
+	// 空应答记为查询失败，未列出的 pay_status 视为待支付
+	report, _ = New(&trade.FakeTrade{
+		QueryTradeFunc: func(_ context.Context, req *trade.QueryOrderRequest) (*trade.QueryOrderResponse, error) {
+			if req.OutOrderNo == "empty" {
+				return nil, nil
+			}
+			return &trade.QueryOrderResponse{Data: &trade.QueryOrderData{OutOrderNo: req.OutOrderNo, PayStatus: "PROCESS", TotalAmount: 100}}, nil
+		},
+	}).Run(ctx, []LocalOrder{{OutOrderNo: "empty", Status: StatusPaid}, {OutOrderNo: "process", Status: StatusPending, Amount: 100}})
+	if report.Matched != 1 || len(report.Discrepancies) != 1 || report.Discrepancies[0].Kind != KindQueryFailed {
+		t.Fatalf("empty response and processing order: %+v", report)
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err = New(ft).Run(cctx, []LocalOrder{{OutOrderNo: "paid"}}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled run: %v", err)
+	}
+}
+
+func TestReconcileConcurrentInterval(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		interval = 20 * time.Millisecond
+		r        = New(&trade.FakeTrade{}, WithInterval(interval))
+		start    = time.Now()
+		wg       sync.WaitGroup
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = r.wait(ctx)
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < 3*interval {
+		t.Errorf("4 concurrent waits took %v, want at least %v", elapsed, 3*interval)
+	}
+}
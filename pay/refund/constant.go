@@ -0,0 +1,57 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package refund
+
+import "github.com/houseme/bytedance/pay/asyncnotify"
+
+const (
+	// createRefund https://open.douyin.com/api/trade_basic/v1/developer/refund_create/
+	// see: https://developer.open-douyin.com/docs/resource/zh-CN/mini-app/develop/server/trade-system/general/refund/create_refund
+	createRefund = "https://open.douyin.com/api/trade_basic/v1/developer/refund_create/"
+
+	// queryRefund https://open.douyin.com/api/trade_basic/v1/developer/refund_query/
+	// see: https://developer.open-douyin.com/docs/resource/zh-CN/mini-app/develop/server/trade-system/general/refund/query_refund
+	queryRefund = "https://open.douyin.com/api/trade_basic/v1/developer/refund_query/"
+
+	// auditRefund https://open.douyin.com/api/trade_basic/v1/developer/refund_audit_callback/
+	// see: https://developer.open-douyin.com/docs/resource/zh-CN/mini-app/develop/server/trade-system/general/refund/refund_audit_callback
+	auditRefund = "https://open.douyin.com/api/trade_basic/v1/developer/refund_audit_callback/"
+)
+
+const (
+	// StateProcessing 退款状态：PROCESSING：退款中，SUCCESS：退款成功，FAIL：退款失败
+	StateProcessing = "PROCESSING"
+	// StateSuccess 退款状态：PROCESSING：退款中，SUCCESS：退款成功，FAIL：退款失败
+	StateSuccess = "SUCCESS"
+	// StateFail 退款状态：PROCESSING：退款中，SUCCESS：退款成功，FAIL：退款失败
+	StateFail = "FAIL"
+)
+
+const (
+	// ReasonCodeOther 退款原因码：999 其他
+	ReasonCodeOther = 999
+)
+
+const (
+	// AuditApprove 退款审核结果：同意退款，同 asyncnotify.RefundAuditApprove
+	AuditApprove = asyncnotify.RefundAuditApprove
+	// AuditReject 退款审核结果：拒绝退款，同 asyncnotify.RefundAuditReject
+	AuditReject = asyncnotify.RefundAuditReject
+)
@@ -18,3 +18,93 @@
  */
 
 package refund
+
+import (
+    "github.com/houseme/bytedance/pay/trade"
+)
+
+// CreateRefundRequest 发起退款
+type CreateRefundRequest struct {
+    OutOrderNo        string             `json:"out_order_no" description:"开发者侧订单号，长度 <= 64byte"`
+    OutRefundNo       string             `json:"out_refund_no" description:"开发者侧退款单号，长度 <= 64byte"`
+    CpExtra           string             `json:"cp_extra,omitempty" description:"开发者自定义透传字段，长度 <= 2048byte"`
+    OrderEntrySchema  *trade.Schema      `json:"order_entry_schema" description:"退款单详情页 schema"`
+    NotifyURL         string             `json:"notify_url,omitempty" description:"退款结果通知地址，不传使用小程序配置的回调地址，长度 <= 512byte"`
+    ItemOrderDetail   []*ItemOrderDetail `json:"item_order_detail,omitempty" description:"需要发起退款的商品单信息，为空时按订单剩余可退金额整单退款"`
+    RefundTotalAmount int                `json:"refund_total_amount,omitempty" description:"退款总金额，单位分，由 item_order_detail 汇总得到"`
+    RefundReason      []*RefundReason    `json:"refund_reason,omitempty" description:"退款原因"`
+}
+
+// ItemOrderDetail 商品单退款信息
+type ItemOrderDetail struct {
+    ItemOrderID  string `json:"item_order_id" description:"商品单号"`
+    RefundAmount int    `json:"refund_amount" description:"该商品单退款金额，单位分"`
+}
+
+// RefundReason 退款原因
+type RefundReason struct {
+    Code int    `json:"code" description:"退款原因码，999 其他"`
+    Text string `json:"text" description:"退款原因描述，长度 <= 50 个字符"`
+}
+
+// CreateRefundResponse 发起退款
+type CreateRefundResponse struct {
+    Data   *CreateRefundData `json:"data"`
+    ErrNo  int               `json:"err_no"`
+    ErrMsg string            `json:"err_msg"`
+    LogID  string            `json:"log_id"`
+}
+
+// CreateRefundData 发起退款
+type CreateRefundData struct {
+    RefundID            string `json:"refund_id" description:"抖音开平侧退款单号"`
+    RefundAuditDeadline int64  `json:"refund_audit_deadline" description:"退款审核的最后期限，13 位毫秒时间戳"`
+}
+
+// QueryRefundRequest 查询退款，refund_id、out_refund_no、order_id 三选一
+type QueryRefundRequest struct {
+    RefundID    string `json:"refund_id,omitempty" description:"抖音开平侧退款单号"`
+    OutRefundNo string `json:"out_refund_no,omitempty" description:"开发者侧退款单号"`
+    OrderID     string `json:"order_id,omitempty" description:"抖音开平侧订单号，查询该订单下的全部退款单"`
+}
+
+// QueryRefundResponse 查询退款
+type QueryRefundResponse struct {
+    Data   *QueryRefundData `json:"data"`
+    ErrNo  int              `json:"err_no"`
+    ErrMsg string           `json:"err_msg"`
+    LogID  string           `json:"log_id"`
+}
+
+// QueryRefundData 查询退款
+type QueryRefundData struct {
+    RefundList []*RefundInfo `json:"refund_list"`
+}
+
+// RefundInfo 退款单信息
+type RefundInfo struct {
+    RefundID          string             `json:"refund_id"`
+    OutRefundNo       string             `json:"out_refund_no"`
+    OrderID           string             `json:"order_id"`
+    RefundStatus      string             `json:"refund_status" description:"退款状态：PROCESSING：退款中，SUCCESS：退款成功，FAIL：退款失败"`
+    RefundTotalAmount int                `json:"refund_total_amount" description:"退款金额，单位分"`
+    RefundAt          int64              `json:"refund_at" description:"退款时间，13 位毫秒时间戳，只有已退款才有退款时间"`
+    Message           string             `json:"message" description:"退款结果信息，可以通过该字段了解退款失败原因"`
+    CpExtra           string             `json:"cp_extra"`
+    CreateAt          int64              `json:"create_at" description:"退款创建时间，13 位毫秒时间戳"`
+    ItemOrderDetail   []*ItemOrderDetail `json:"item_order_detail"`
+}
+
+// AuditRefundRequest 同步退款审核结果
+type AuditRefundRequest struct {
+    RefundID          string `json:"refund_id" description:"抖音开平侧退款单号"`
+    RefundAuditStatus int    `json:"refund_audit_status" description:"审核结果，1：同意退款，2：拒绝退款"`
+    DenyMessage       string `json:"deny_message,omitempty" description:"拒绝退款的原因，拒绝时必填，长度 <= 50 个字符"`
+}
+
+// AuditRefundResponse 同步退款审核结果
+type AuditRefundResponse struct {
+    ErrNo  int    `json:"err_no"`
+    ErrMsg string `json:"err_msg"`
+    LogID  string `json:"log_id"`
+}
@@ -21,15 +21,230 @@
 package refund
 
 import (
-    "github.com/houseme/bytedance/credential"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/credential"
+	"github.com/houseme/bytedance/pay/trade"
+	"github.com/houseme/bytedance/utility/base"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type IRefund -impl Refund
+
+// IRefund Refund 服务接口，便于替换为 FakeRefund 等测试替身
+type IRefund interface {
+	// CreateRefund 发起退款
+	CreateRefund(ctx context.Context, req *CreateRefundRequest) (*CreateRefundResponse, error)
+	// QueryRefund 查询退款
+	QueryRefund(ctx context.Context, req *QueryRefundRequest) (*QueryRefundResponse, error)
+	// AuditRefund 同步退款审核结果
+	AuditRefund(ctx context.Context, req *AuditRefundRequest) (*AuditRefundResponse, error)
+}
+
 // Refund merchant account refund
 type Refund struct {
-    ctxCfg *credential.ContextConfig
+	ctxCfg *credential.ContextConfig
+	trade  trade.ITrade
 }
 
 // NewRefund init
 func NewRefund(cfg *credential.ContextConfig) *Refund {
-    return &Refund{ctxCfg: cfg}
+	return &Refund{ctxCfg: cfg, trade: trade.NewTrade(cfg)}
+}
+
+// getAccessToken 获取 access_token
+func (t *Refund) getAccessToken(ctx context.Context) (accessToken string, err error) {
+	var clientToken *credential.ClientToken
+	if clientToken, err = t.ctxCfg.GetClientToken(ctx); err != nil {
+		return "", err
+	}
+	if clientToken == nil {
+		return "", base.ErrClientTokenIsEmpty
+	}
+
+	if strings.TrimSpace(clientToken.AccessToken) == "" {
+		return "", base.ErrClientAccessTokenIsEmpty
+	}
+
+	return clientToken.AccessToken, nil
+}
+
+// setContext 设置上下文
+func (t *Refund) setContext(ctx context.Context) (context.Context, error) {
+	accessToken, err := t.getAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(
+		ctx,
+		config.AccessTokenKey,
+		accessToken,
+	)
+	return ctx, nil
+}
+
+// CreateRefund 发起退款，item_order_detail 为空时按各商品单剩余可退金额整单退款。
+// 发起前会先调用查询订单与查询退款两个接口校验可退金额，累计退款金额超过实付金额时返回 base.ErrRefundAmountExceeded；
+// 补全的 item_order_detail 与 refund_total_amount 只用于本次请求，不会修改 req
+func (t *Refund) CreateRefund(ctx context.Context, req *CreateRefundRequest) (resp *CreateRefundResponse, err error) {
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
+	}
+	if req.OutOrderNo == "" {
+		return nil, base.ErrParamKeyValueEmpty("OutOrderNo")
+	}
+	if req.OutRefundNo == "" {
+		return nil, base.ErrParamKeyValueEmpty("OutRefundNo")
+	}
+	if req.OrderEntrySchema == nil || req.OrderEntrySchema.Path == "" {
+		return nil, base.ErrParamKeyValueEmpty("OrderEntrySchema")
+	}
+	if req, err = t.checkRefundable(ctx, req); err != nil {
+		return nil, err
+	}
+
+	if ctx, err = t.setContext(ctx); err != nil {
+		return nil, err
+	}
+	var response []byte
+	if response, err = t.ctxCfg.Request().PostJSON(ctx, createRefund, *req); err != nil {
+		return nil, err
+	}
+	resp = &CreateRefundResponse{}
+	err = json.Unmarshal(response, resp)
+	return
+}
+
+// QueryRefund 查询退款
+func (t *Refund) QueryRefund(ctx context.Context, req *QueryRefundRequest) (resp *QueryRefundResponse, err error) {
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
+	}
+	if req.RefundID == "" && req.OutRefundNo == "" && req.OrderID == "" {
+		return nil, base.ErrParamKeyValueEmpty("RefundID")
+	}
+
+	if ctx, err = t.setContext(ctx); err != nil {
+		return nil, err
+	}
+	var response []byte
+	if response, err = t.ctxCfg.Request().PostJSON(ctx, queryRefund, *req); err != nil {
+		return nil, err
+	}
+	resp = &QueryRefundResponse{}
+	err = json.Unmarshal(response, resp)
+	return
+}
+
+// AuditRefund 同步退款审核结果，用于退款申请回调中暂不审核、稍后异步提交审核结果的场景
+func (t *Refund) AuditRefund(ctx context.Context, req *AuditRefundRequest) (resp *AuditRefundResponse, err error) {
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
+	}
+	if req.RefundID == "" {
+		return nil, base.ErrParamKeyValueEmpty("RefundID")
+	}
+	if req.RefundAuditStatus != AuditApprove && req.RefundAuditStatus != AuditReject {
+		return nil, base.ErrParamKeyValueEmpty("RefundAuditStatus")
+	}
+	if req.RefundAuditStatus == AuditReject && req.DenyMessage == "" {
+		return nil, base.ErrParamKeyValueEmpty("DenyMessage")
+	}
+
+	if ctx, err = t.setContext(ctx); err != nil {
+		return nil, err
+	}
+	var response []byte
+	if response, err = t.ctxCfg.Request().PostJSON(ctx, auditRefund, *req); err != nil {
+		return nil, err
+	}
+	resp = &AuditRefundResponse{}
+	err = json.Unmarshal(response, resp)
+	return
+}
+
+// checkRefundable 校验退款金额，返回补全了 item_order_detail 与 refund_total_amount 的请求副本，
+// 失败状态的退款单与同一 out_refund_no 的重复提交不计入已退金额
+func (t *Refund) checkRefundable(ctx context.Context, in *CreateRefundRequest) (*CreateRefundRequest, error) {
+	order, err := t.trade.QueryTrade(ctx, &trade.QueryOrderRequest{OutOrderNo: in.OutOrderNo})
+	if err != nil {
+		return nil, err
+	}
+	if order.ErrNo != 0 {
+		return nil, base.Error{ErrCode: order.ErrNo, ErrMsg: order.ErrMsg}
+	}
+	if order.Data == nil {
+		return nil, fmt.Errorf("%w: %s", base.ErrOrderNotFound, in.OutOrderNo)
+	}
+
+	refunds, err := t.QueryRefund(ctx, &QueryRefundRequest{OrderID: order.Data.OrderID})
+	if err != nil {
+		return nil, err
+	}
+	if refunds.ErrNo != 0 {
+		return nil, base.Error{ErrCode: refunds.ErrNo, ErrMsg: refunds.ErrMsg}
+	}
+	req := *in
+	req.ItemOrderDetail = append([]*ItemOrderDetail(nil), in.ItemOrderDetail...)
+	var (
+		refundedTotal int
+		refunded      = make(map[string]int)
+	)
+	if refunds.Data != nil {
+		for _, r := range refunds.Data.RefundList {
+			if r.RefundStatus == StateFail || r.OutRefundNo == req.OutRefundNo {
+				continue
+			}
+			refundedTotal += r.RefundTotalAmount
+			for _, item := range r.ItemOrderDetail {
+				refunded[item.ItemOrderID] += item.RefundAmount
+			}
+		}
+	}
+
+	items := make(map[string]int, len(order.Data.ItemOrderList))
+	for _, item := range order.Data.ItemOrderList {
+		items[item.ItemOrderID] = item.ItemOrderAmount
+	}
+	if len(req.ItemOrderDetail) == 0 {
+		for _, item := range order.Data.ItemOrderList {
+			if remain := item.ItemOrderAmount - refunded[item.ItemOrderID]; remain > 0 {
+				req.ItemOrderDetail = append(req.ItemOrderDetail, &ItemOrderDetail{
+					ItemOrderID:  item.ItemOrderID,
+					RefundAmount: remain,
+				})
+			}
+		}
+		if len(req.ItemOrderDetail) == 0 {
+			return nil, fmt.Errorf("%w: order %s has been fully refunded", base.ErrRefundAmountExceeded, req.OutOrderNo)
+		}
+	}
+
+	var total int
+	for _, detail := range req.ItemOrderDetail {
+		amount, ok := items[detail.ItemOrderID]
+		if !ok {
+			return nil, fmt.Errorf("%w: item_order_id %s in order %s", base.ErrItemOrderNotFound, detail.ItemOrderID, req.OutOrderNo)
+		}
+		if detail.RefundAmount <= 0 {
+			return nil, fmt.Errorf("%w: item_order_id %s refund_amount %d", base.ErrInvalidAmount, detail.ItemOrderID, detail.RefundAmount)
+		}
+		if refunded[detail.ItemOrderID]+detail.RefundAmount > amount {
+			return nil, fmt.Errorf("%w: item_order_id %s refunded %d, requested %d, item amount %d",
+				base.ErrRefundAmountExceeded, detail.ItemOrderID, refunded[detail.ItemOrderID], detail.RefundAmount, amount)
+		}
+		total += detail.RefundAmount
+	}
+	if paid := order.Data.TotalAmount - order.Data.DiscountAmount; refundedTotal+total > paid {
+		return nil, fmt.Errorf("%w: order %s refunded %d, requested %d, paid %d",
+			base.ErrRefundAmountExceeded, req.OutOrderNo, refundedTotal, total, paid)
+	}
+	if req.RefundTotalAmount != 0 && req.RefundTotalAmount != total {
+		return nil, fmt.Errorf("%w: refund_total_amount %d, item_order_detail total %d", base.ErrRefundAmountMismatch, req.RefundTotalAmount, total)
+	}
+	req.RefundTotalAmount = total
+	return &req, nil
 }
@@ -34,3 +34,10 @@ const (
     // StateFail 分账状态：INIT：初始化，PROCESSING：处理中，SUCCESS：处理成功，FAIL：处理失败
     StateFail = "FAIL"
 )
+
+const (
+    // maxSettleParamsLength settle_params 最大长度，单位 byte
+    maxSettleParamsLength = 512
+    // basisPointsTotal 分账比例的基数，万分之一为单位
+    basisPointsTotal = 10000
+)
@@ -28,6 +28,9 @@ type ApplySettleRequest struct {
     SettleParams string `json:"settle_params" desc:"其他分账方（除卖家之外的），长度 <= 512 字节 [{\"merchant_uid\":\"merchant_uid_example\",\"amount\":100}]"`
     Ext          string `json:"ext" desc:"开发者自定义透传字段，不支持二进制，会在查询分账接口 cp_extra 字段原样返回，长度 <= 2048 字节"`
     NotifyURL    string `json:"notify_url"`
+    
+    // Receivers 其他分账方，非空时由 Apply 序列化为 settle_params，不能与 SettleParams 同时设置
+    Receivers []*OtherSettleParam `json:"-"`
 }
 
 // OtherSettleParam 其他结算参数
@@ -22,21 +22,35 @@ package settle
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/houseme/bytedance/config"
 	"github.com/houseme/bytedance/credential"
+	"github.com/houseme/bytedance/pay/money"
+	"github.com/houseme/bytedance/pay/trade"
 	"github.com/houseme/bytedance/utility/base"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type ISettle -impl Settle
+
+// ISettle Settle 服务接口，便于替换为 FakeSettle 等测试替身
+type ISettle interface {
+	// Apply 申请结算
+	Apply(ctx context.Context, req *ApplySettleRequest) (*ApplySettleResponse, error)
+	// Query 查询结算
+	Query(ctx context.Context, req *QuerySettleRequest) (*QuerySettleResponse, error)
+}
+
 // Settle merchant account settle
 type Settle struct {
 	ctxCfg *credential.ContextConfig
+	trade  trade.ITrade
 }
 
 // NewSettle init
 func NewSettle(cfg *credential.ContextConfig) *Settle {
-	return &Settle{ctxCfg: cfg}
+	return &Settle{ctxCfg: cfg, trade: trade.NewTrade(cfg)}
 }
 
 // getAccessToken 获取 access_token
@@ -70,11 +84,16 @@ func (t *Settle) setContext(ctx context.Context) (context.Context, error) {
 	return ctx, nil
 }
 
-// Apply 申请结算
+// Apply 申请结算，设置了 Receivers 时序列化为 settle_params；
+// 发送前校验 settle_params 不超过 512 字节，且其他分账方合计不超过查询到的订单（或商品单）金额。
+// 序列化写入请求副本，不修改 req，同一请求可直接重试
 func (t *Settle) Apply(ctx context.Context, req *ApplySettleRequest) (resp *ApplySettleResponse, err error) {
 	if req == nil {
 		return nil, base.ErrRequestIsEmpty
 	}
+	if req, err = t.checkReceivers(ctx, req); err != nil {
+		return nil, err
+	}
 
 	if ctx, err = t.setContext(ctx); err != nil {
 		return nil, err
@@ -105,3 +124,65 @@ func (t *Settle) Query(ctx context.Context, req *QuerySettleRequest) (resp *Quer
 	err = json.Unmarshal(response, resp)
 	return
 }
+
+// checkReceivers 序列化并校验其他分账方，返回写入了 settle_params 的请求副本
+func (t *Settle) checkReceivers(ctx context.Context, in *ApplySettleRequest) (*ApplySettleRequest, error) {
+	req := *in
+	if len(req.Receivers) > 0 {
+		if req.SettleParams != "" {
+			return nil, fmt.Errorf("%w: Receivers and SettleParams are both set", ErrInvalidSplitRule)
+		}
+		params, err := json.Marshal(req.Receivers)
+		if err != nil {
+			return nil, err
+		}
+		req.SettleParams = string(params)
+	}
+	if req.SettleParams == "" {
+		return &req, nil
+	}
+	if len(req.SettleParams) > maxSettleParamsLength {
+		return nil, fmt.Errorf("%w: %d bytes", ErrSettleParamsTooLong, len(req.SettleParams))
+	}
+	var receivers []*OtherSettleParam
+	if err := json.Unmarshal([]byte(req.SettleParams), &receivers); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSplitRule, err)
+	}
+	var sum money.Money
+	for i, r := range receivers {
+		if r == nil || r.MerchantUid == "" || r.Amount <= 0 {
+			return nil, fmt.Errorf("%w: receivers[%d] needs merchant_uid and positive amount", ErrInvalidSplitRule, i)
+		}
+		var err error
+		if sum, err = sum.Add(money.FromCents(r.Amount)); err != nil {
+			return nil, err
+		}
+	}
+
+	order, err := t.trade.QueryTrade(ctx, &trade.QueryOrderRequest{OutOrderNo: req.OutOrderNo})
+	if err != nil {
+		return nil, err
+	}
+	if order.ErrNo != 0 {
+		return nil, base.Error{ErrCode: order.ErrNo, ErrMsg: order.ErrMsg}
+	}
+	if order.Data == nil {
+		return nil, fmt.Errorf("%w: %s", base.ErrOrderNotFound, req.OutOrderNo)
+	}
+	amount := order.Data.TotalAmount - order.Data.DiscountAmount
+	if req.ItemOrderID != "" {
+		amount = -1
+		for _, item := range order.Data.ItemOrderList {
+			if item.ItemOrderID == req.ItemOrderID {
+				amount = item.ItemOrderAmount
+			}
+		}
+		if amount < 0 {
+			return nil, fmt.Errorf("%w: item_order_id %s in order %s", base.ErrItemOrderNotFound, req.ItemOrderID, req.OutOrderNo)
+		}
+	}
+	if sum > money.FromCents(amount) {
+		return nil, fmt.Errorf("%w: receivers %s, order %s", ErrSettleAmountExceeded, sum, money.FromCents(amount))
+	}
+	return &req, nil
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package settle
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/houseme/bytedance/pay/money"
+)
+
+var (
+	// ErrInvalidSplitRule 分账规则不合法
+	ErrInvalidSplitRule = errors.New("settle: invalid split rule")
+	// ErrSettleAmountExceeded 分账金额超过订单可分账金额
+	ErrSettleAmountExceeded = errors.New("settle: receivers amount exceeds order amount")
+	// ErrSettleParamsTooLong settle_params 超过 512 字节
+	ErrSettleParamsTooLong = errors.New("settle: settle_params exceeds 512 bytes")
+)
+
+// Remainder 按比例分账时舍去零头的归属
+type Remainder int
+
+const (
+	// RemainderToSeller 零头留给卖家，默认
+	RemainderToSeller Remainder = iota
+	// RemainderToFirst 零头全部给第一个按比例分账的分账方
+	RemainderToFirst
+	// RemainderLargest 按最大余数法逐分分配给小数部分最大的分账方
+	RemainderLargest
+)
+
+// Rule 分账规则，BasisPoints 与 Amount 二选一
+type Rule struct {
+	MerchantUID string
+	BasisPoints int // 分账比例，万分之一为单位，如 3000 表示 30%
+	Amount      int // 固定分账金额，单位分
+}
+
+// Split 按规则计算各分账方金额，total 为可分账金额，单位分；分账合计超过 total 时返回 ErrSettleAmountExceeded
+func Split(total int, rules []Rule, remainder Remainder) ([]*OtherSettleParam, error) {
+	if total < 0 {
+		return nil, fmt.Errorf("%w: total %d is negative", ErrInvalidSplitRule, total)
+	}
+	var (
+		receivers = make([]*OtherSettleParam, len(rules))
+		fractions = make([]int64, len(rules))
+		percent   []int
+		sumBP     int
+		sum       money.Money
+	)
+	for i, rule := range rules {
+		switch {
+		case rule.MerchantUID == "":
+			return nil, fmt.Errorf("%w: rules[%d] merchant_uid is empty", ErrInvalidSplitRule, i)
+		case (rule.BasisPoints > 0) == (rule.Amount > 0):
+			return nil, fmt.Errorf("%w: rules[%d] must set exactly one positive basis points or amount", ErrInvalidSplitRule, i)
+		case rule.BasisPoints < 0 || rule.Amount < 0:
+			return nil, fmt.Errorf("%w: rules[%d] is negative", ErrInvalidSplitRule, i)
+		}
+
+		amount := money.FromCents(rule.Amount)
+		if rule.BasisPoints > 0 {
+			exact, err := money.FromCents(total).Mul(rule.BasisPoints)
+			if err != nil {
+				return nil, err
+			}
+			amount = exact / basisPointsTotal
+			fractions[i] = int64(exact % basisPointsTotal)
+			percent = append(percent, i)
+			sumBP += rule.BasisPoints
+		}
+		cents, err := amount.Cents()
+		if err != nil {
+			return nil, err
+		}
+		receivers[i] = &OtherSettleParam{MerchantUid: rule.MerchantUID, Amount: cents}
+		if sum, err = sum.Add(amount); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(percent) > 0 && remainder != RemainderToSeller {
+		exact, err := money.FromCents(total).Mul(sumBP)
+		if err != nil {
+			return nil, err
+		}
+		var floors money.Money
+		for _, i := range percent {
+			floors += money.FromCents(receivers[i].Amount)
+		}
+		left := int(exact/basisPointsTotal - floors)
+		switch remainder {
+		case RemainderToFirst:
+			receivers[percent[0]].Amount += left
+		case RemainderLargest:
+			order := append([]int(nil), percent...)
+			sort.SliceStable(order, func(a, b int) bool {
+				return fractions[order[a]] > fractions[order[b]]
+			})
+			for k := 0; k < left; k++ {
+				receivers[order[k%len(order)]].Amount++
+			}
+		}
+		sum += money.FromCents(left)
+	}
+
+	if sum > money.FromCents(total) {
+		return nil, fmt.Errorf("%w: receivers %s, order %s", ErrSettleAmountExceeded, sum, money.FromCents(total))
+	}
+	return receivers, nil
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package settle
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	rules := []Rule{
+		{MerchantUID: "b", BasisPoints: 3334},
+		{MerchantUID: "a", BasisPoints: 3333},
+		{MerchantUID: "c", Amount: 100},
+	}
+	cases := []struct {
+		remainder Remainder
+		want      []int
+	}{
+		{RemainderToSeller, []int{333, 332, 100}},
+		{RemainderToFirst, []int{334, 332, 100}},
+		{RemainderLargest, []int{333, 333, 100}},
+	}
+	for _, c := range cases {
+		got, err := Split(999, rules, c.remainder)
+		if err != nil {
+			t.Fatalf("Split(%d) err = %v", c.remainder, err)
+		}
+		for i, r := range got {
+			if r.MerchantUid != rules[i].MerchantUID || r.Amount != c.want[i] {
+				t.Errorf("Split(%d)[%d] = %+v, want %d", c.remainder, i, r, c.want[i])
+			}
+		}
+	}
+
+	if _, err := Split(100, []Rule{{MerchantUID: "a", BasisPoints: 5000}, {MerchantUID: "b", Amount: 60}}, RemainderToSeller); !errors.Is(err, ErrSettleAmountExceeded) {
+		t.Errorf("Split() over amount err = %v, want ErrSettleAmountExceeded", err)
+	}
+	if _, err := Split(100, []Rule{{MerchantUID: "a", BasisPoints: 10, Amount: 10}}, RemainderToSeller); !errors.Is(err, ErrInvalidSplitRule) {
+		t.Errorf("Split() ambiguous rule err = %v, want ErrInvalidSplitRule", err)
+	}
+}
@@ -0,0 +1,386 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+// Package settlement 分账编排：批量提交到期订单的分账，并通过查询与回调跟踪分账状态
+package settlement
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/houseme/bytedance/pay/asyncnotify"
+	"github.com/houseme/bytedance/pay/settle"
+)
+
+const (
+	// defaultConcurrency 默认并发数
+	defaultConcurrency = 8
+	// defaultPrefix 默认分账单号前缀
+	defaultPrefix = "stl"
+	// StatePending 未到分账时间，尚未提交
+	StatePending = "PENDING"
+)
+
+// Order 待分账订单
+type Order struct {
+	OutOrderNo  string
+	ItemOrderID string
+	SettleAfter time.Time // 最早分账时间，如履约完成后 T+N
+	SettleDesc  string
+	Receivers   []*settle.OtherSettleParam
+	Ext         string
+	NotifyURL   string
+}
+
+// key 订单的唯一标识
+func (o *Order) key() string {
+	return o.OutOrderNo + "|" + o.ItemOrderID
+}
+
+// Task 分账任务
+type Task struct {
+	Order       *Order
+	OutSettleNo string
+	SettleID    string
+	State       string // StatePending 或 settle.StateInit/StateProcessing/StateSuccess/StateFail
+	Attempt     int    // 平台返回分账失败后重试的次数，参与生成 OutSettleNo
+	Err         error  // 最近一次失败原因
+	UpdatedAt   time.Time
+}
+
+// Report 分账结果报告
+type Report struct {
+	Total      int
+	Pending    int
+	Init       int
+	Processing int
+	Success    int
+	Fail       int
+	Failures   []Task // 可通过 Retry 重试的失败任务
+}
+
+// Orchestrator 分账编排
+type Orchestrator struct {
+	settle      settle.ISettle
+	concurrency int
+	prefix      string
+	now         func() time.Time
+
+	mu      sync.Mutex
+	tasks   map[string]*Task  // 订单 key -> 任务
+	settles map[string]string // OutSettleNo -> 订单 key
+}
+
+type options struct {
+	concurrency int
+	prefix      string
+	now         func() time.Time
+}
+
+// Option orchestrator option
+type Option func(*options)
+
+// WithConcurrency 设置提交与查询的最大并发数
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+// WithPrefix 设置分账单号前缀
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithClock 设置时间来源
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// New 创建分账编排，s 通常为 pay.Pay.Settle()
+func New(s settle.ISettle, opts ...Option) *Orchestrator {
+	op := options{
+		concurrency: defaultConcurrency,
+		prefix:      defaultPrefix,
+		now:         time.Now,
+	}
+	for _, option := range opts {
+		option(&op)
+	}
+	if op.concurrency < 1 {
+		op.concurrency = 1
+	}
+	return &Orchestrator{
+		settle:      s,
+		concurrency: op.concurrency,
+		prefix:      op.prefix,
+		now:         op.now,
+		tasks:       make(map[string]*Task),
+		settles:     make(map[string]string),
+	}
+}
+
+// OutSettleNo 根据订单与重试次数生成确定性的分账单号，长度不超过 64 字节
+func OutSettleNo(prefix, outOrderNo, itemOrderID string, attempt int) string {
+	sum := sha1.Sum([]byte(outOrderNo + "|" + itemOrderID + "|" + strconv.Itoa(attempt)))
+	return prefix + hex.EncodeToString(sum[:])[:32]
+}
+
+// Add 添加待分账订单，已存在的订单忽略
+func (o *Orchestrator) Add(orders ...*Order) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, order := range orders {
+		if _, ok := o.tasks[order.key()]; ok {
+			continue
+		}
+		task := &Task{
+			Order:       order,
+			OutSettleNo: OutSettleNo(o.prefix, order.OutOrderNo, order.ItemOrderID, 0),
+			State:       StatePending,
+			UpdatedAt:   o.now(),
+		}
+		o.tasks[order.key()] = task
+		o.settles[task.OutSettleNo] = order.key()
+	}
+}
+
+// Submit 提交已到分账时间的待提交任务，返回提交后的报告
+func (o *Orchestrator) Submit(ctx context.Context) *Report {
+	now := o.now()
+	o.run(ctx, func(t *Task) bool {
+		if t.State == StatePending && !now.Before(t.Order.SettleAfter) {
+			t.State = settle.StateInit
+		}
+		return t.State == settle.StateInit
+	}, o.apply)
+	return o.Report()
+}
+
+// Poll 查询处理中的任务，返回查询后的报告
+func (o *Orchestrator) Poll(ctx context.Context) *Report {
+	o.run(ctx, func(t *Task) bool {
+		return t.State == settle.StateProcessing
+	}, o.query)
+	return o.Report()
+}
+
+// HandleCallback 处理分账结果回调，可直接注册到 webhook.Router.OnSettle；未知的分账单忽略
+func (o *Orchestrator) HandleCallback(_ context.Context, data *asyncnotify.SettleData) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	key, ok := o.settles[data.OutSettleNo]
+	if !ok {
+		return nil
+	}
+	task := o.tasks[key]
+	if task.OutSettleNo != data.OutSettleNo {
+		return nil
+	}
+	if data.SettleID != "" {
+		task.SettleID = data.SettleID
+	}
+	o.transition(task, data.Status, data.Message)
+	return nil
+}
+
+// Retry 将失败的任务重新置为待提交，未指定 outOrderNos 时重试全部失败任务，返回重试的任务数；
+// 平台返回分账失败的任务会使用新的 OutSettleNo，提交出错的任务沿用原分账单号以保证幂等
+func (o *Orchestrator) Retry(outOrderNos ...string) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	wanted := make(map[string]bool, len(outOrderNos))
+	for _, no := range outOrderNos {
+		wanted[no] = true
+	}
+	var n int
+	for key, task := range o.tasks {
+		if task.State != settle.StateFail || (len(wanted) > 0 && !wanted[task.Order.OutOrderNo]) {
+			continue
+		}
+		if task.SettleID != "" {
+			task.Attempt++
+			task.SettleID = ""
+			task.OutSettleNo = OutSettleNo(o.prefix, task.Order.OutOrderNo, task.Order.ItemOrderID, task.Attempt)
+			o.settles[task.OutSettleNo] = key
+		}
+		task.State = settle.StateInit
+		task.Err = nil
+		task.UpdatedAt = o.now()
+		n++
+	}
+	return n
+}
+
+// Report 当前的分账结果报告
+func (o *Orchestrator) Report() *Report {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	r := &Report{Total: len(o.tasks)}
+	for _, task := range o.tasks {
+		switch task.State {
+		case StatePending:
+			r.Pending++
+		case settle.StateInit:
+			r.Init++
+		case settle.StateProcessing:
+			r.Processing++
+		case settle.StateSuccess:
+			r.Success++
+		case settle.StateFail:
+			r.Fail++
+			r.Failures = append(r.Failures, *task)
+		}
+	}
+	sort.Slice(r.Failures, func(i, j int) bool {
+		return r.Failures[i].OutSettleNo < r.Failures[j].OutSettleNo
+	})
+	return r
+}
+
+// run 选出满足条件的任务并以有限并发执行 fn，ctx 取消后不再启动新的任务
+func (o *Orchestrator) run(ctx context.Context, pick func(t *Task) bool, fn func(ctx context.Context, t Task) (*Task, error)) {
+	o.mu.Lock()
+	var picked []Task
+	for _, task := range o.tasks {
+		if pick(task) {
+			picked = append(picked, *task)
+		}
+	}
+	o.mu.Unlock()
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, o.concurrency)
+	)
+	for _, task := range picked {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+			if ctx.Err() != nil {
+				<-sem
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(task Task) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			updated, err := fn(ctx, task)
+			o.mu.Lock()
+			defer o.mu.Unlock()
+			current := o.tasks[task.Order.key()]
+			if current.OutSettleNo != task.OutSettleNo {
+				return
+			}
+			if err != nil {
+				current.Err = err
+				current.UpdatedAt = o.now()
+				if current.State == settle.StateInit {
+					current.State = settle.StateFail
+				}
+				return
+			}
+			if updated != nil {
+				if updated.SettleID != "" {
+					current.SettleID = updated.SettleID
+				}
+				o.transition(current, updated.State, "")
+			}
+		}(task)
+	}
+	wg.Wait()
+}
+
+// apply 提交分账
+func (o *Orchestrator) apply(ctx context.Context, t Task) (*Task, error) {
+	resp, err := o.settle.Apply(ctx, &settle.ApplySettleRequest{
+		OutOrderNo:  t.Order.OutOrderNo,
+		OutSettleNo: t.OutSettleNo,
+		ItemOrderID: t.Order.ItemOrderID,
+		SettleDesc:  t.Order.SettleDesc,
+		Ext:         t.Order.Ext,
+		NotifyURL:   t.Order.NotifyURL,
+		Receivers:   t.Order.Receivers,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.ErrNo != 0 {
+		return nil, fmt.Errorf("settle apply err_no: %d, err_msg: %s", resp.ErrNo, resp.ErrMsg)
+	}
+	t.State = settle.StateProcessing
+	if resp.Data != nil {
+		t.SettleID = resp.Data.SettleID
+	}
+	return &t, nil
+}
+
+// query 查询分账状态
+func (o *Orchestrator) query(ctx context.Context, t Task) (*Task, error) {
+	resp, err := o.settle.Query(ctx, &settle.QuerySettleRequest{OutSettleNo: t.OutSettleNo})
+	if err != nil {
+		return nil, err
+	}
+	if resp.ErrNo != 0 {
+		return nil, fmt.Errorf("settle query err_no: %d, err_msg: %s", resp.ErrNo, resp.ErrMsg)
+	}
+	for _, data := range resp.Data {
+		if data.OutSettleID == t.OutSettleNo {
+			t.State = data.SettleStatus
+			t.SettleID = data.SettleID
+			return &t, nil
+		}
+	}
+	return nil, nil
+}
+
+// transition 更新任务状态，SUCCESS 与 FAIL 为终态，不会被迟到的查询结果或回调改变，失败任务只能通过 Retry 重新提交
+func (o *Orchestrator) transition(task *Task, state, message string) {
+	if task.State == settle.StateSuccess || task.State == settle.StateFail || state == "" || state == task.State {
+		return
+	}
+	switch state {
+	case settle.StateInit:
+		return
+	case settle.StateFail:
+		task.Err = fmt.Errorf("settle %s failed", task.OutSettleNo)
+		if message != "" {
+			task.Err = fmt.Errorf("settle %s failed: %s", task.OutSettleNo, message)
+		}
+	case settle.StateSuccess, settle.StateProcessing:
+		task.Err = nil
+	}
+	task.State = state
+	task.UpdatedAt = o.now()
+}
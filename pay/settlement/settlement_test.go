@@ -0,0 +1,146 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package settlement
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/houseme/bytedance/pay/asyncnotify"
+	"github.com/houseme/bytedance/pay/settle"
+)
+
+func TestOrchestrator(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	var (
+		applied  atomic.Int32
+		inFlight atomic.Int32
+		peak     atomic.Int32
+	)
+	fake := &settle.FakeSettle{
+		ApplyFunc: func(_ context.Context, req *settle.ApplySettleRequest) (*settle.ApplySettleResponse, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			time.Sleep(time.Millisecond)
+			applied.Add(1)
+			if req.OutOrderNo == "broken" {
+				return nil, errors.New("network down")
+			}
+			return &settle.ApplySettleResponse{Data: &settle.ApplySettleData{SettleID: "id-" + req.OutOrderNo}}, nil
+		},
+		QueryFunc: func(_ context.Context, req *settle.QuerySettleRequest) (*settle.QuerySettleResponse, error) {
+			return &settle.QuerySettleResponse{Data: []*settle.QuerySettleData{{OutSettleID: req.OutSettleNo, SettleStatus: settle.StateSuccess}}}, nil
+		},
+	}
+	orch := New(fake, WithConcurrency(2), WithClock(func() time.Time { return now }))
+
+	orders := []*Order{
+		{OutOrderNo: "o1", SettleAfter: now.Add(-time.Hour)},
+		{OutOrderNo: "o2", SettleAfter: now.Add(-time.Hour)},
+		{OutOrderNo: "o3", SettleAfter: now.Add(-time.Hour)},
+		{OutOrderNo: "broken", SettleAfter: now.Add(-time.Hour)},
+		{OutOrderNo: "later", SettleAfter: now.Add(72 * time.Hour)},
+	}
+	orch.Add(orders...)
+	orch.Add(orders[0])
+
+	report := orch.Submit(ctx)
+	if report.Total != 5 || report.Pending != 1 || report.Processing != 3 || report.Fail != 1 || applied.Load() != 4 {
+		t.Fatalf("Submit() report = %+v, applied %d", report, applied.Load())
+	}
+	if peak.Load() > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", peak.Load())
+	}
+	failed := report.Failures[0]
+	if failed.Order.OutOrderNo != "broken" || failed.OutSettleNo != OutSettleNo(defaultPrefix, "broken", "", 0) {
+		t.Errorf("failure = %+v", failed)
+	}
+
+	o1 := OutSettleNo(defaultPrefix, "o1", "", 0)
+	_ = orch.HandleCallback(ctx, &asyncnotify.SettleData{OutSettleNo: o1, Status: settle.StateFail, Message: "余额不足"})
+	if report = orch.Poll(ctx); report.Success != 2 || report.Fail != 2 {
+		t.Fatalf("Poll() report = %+v", report)
+	}
+
+	if n := orch.Retry(); n != 2 {
+		t.Fatalf("Retry() = %d, want 2", n)
+	}
+	fake.ApplyFunc = func(_ context.Context, req *settle.ApplySettleRequest) (*settle.ApplySettleResponse, error) {
+		if req.OutOrderNo == "o1" && req.OutSettleNo == o1 {
+			t.Errorf("retry of platform-failed settle reused out_settle_no %s", o1)
+		}
+		return &settle.ApplySettleResponse{Data: &settle.ApplySettleData{SettleID: "id2-" + req.OutOrderNo}}, nil
+	}
+	orch.Submit(ctx)
+	if report = orch.Poll(ctx); report.Success != 4 || report.Fail != 0 || report.Pending != 1 {
+		t.Errorf("after retry report = %+v", report)
+	}
+}
+
+func TestOrchestratorCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var applied atomic.Int32
+	fake := &settle.FakeSettle{
+		ApplyFunc: func(context.Context, *settle.ApplySettleRequest) (*settle.ApplySettleResponse, error) {
+			applied.Add(1)
+			// 让后续任务先阻塞在并发槽位上，再取消
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+			return &settle.ApplySettleResponse{}, nil
+		},
+	}
+	orch := New(fake, WithConcurrency(1))
+	orch.Add(&Order{OutOrderNo: "o1"}, &Order{OutOrderNo: "o2"}, &Order{OutOrderNo: "o3"})
+	if report := orch.Submit(ctx); applied.Load() != 1 || report.Processing != 1 {
+		t.Errorf("Submit() after cancel applied %d, report %+v, want 1 applied", applied.Load(), report)
+	}
+}
+
+func TestOrchestratorTerminalState(t *testing.T) {
+	ctx := context.Background()
+	var orch *Orchestrator
+	fake := &settle.FakeSettle{
+		ApplyFunc: func(context.Context, *settle.ApplySettleRequest) (*settle.ApplySettleResponse, error) {
+			return &settle.ApplySettleResponse{}, nil
+		},
+		QueryFunc: func(_ context.Context, req *settle.QuerySettleRequest) (*settle.QuerySettleResponse, error) {
+			// 查询期间收到失败回调，查询结果迟到
+			_ = orch.HandleCallback(ctx, &asyncnotify.SettleData{OutSettleNo: req.OutSettleNo, Status: settle.StateFail})
+			return &settle.QuerySettleResponse{Data: []*settle.QuerySettleData{{OutSettleID: req.OutSettleNo, SettleStatus: settle.StateProcessing}}}, nil
+		},
+	}
+	orch = New(fake)
+	orch.Add(&Order{OutOrderNo: "o1"})
+	orch.Submit(ctx)
+	if report := orch.Poll(ctx); report.Fail != 1 || report.Processing != 0 {
+		t.Fatalf("Poll() report = %+v, want the FAIL callback to stick", report)
+	}
+	_ = orch.HandleCallback(ctx, &asyncnotify.SettleData{OutSettleNo: OutSettleNo(defaultPrefix, "o1", "", 0), Status: settle.StateSuccess})
+	if report := orch.Report(); report.Fail != 1 {
+		t.Errorf("Report() after late SUCCESS = %+v, want FAIL kept", report)
+	}
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package trade
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/utility/helper"
+)
+
+const (
+	// authorizationScheme byteAuthorization 签名算法
+	authorizationScheme = "SHA256-RSA2048"
+	// requestOrderMethod tt.requestOrder 签名使用的请求方法
+	requestOrderMethod = "POST"
+	// requestOrderURI tt.requestOrder 签名使用的 URI
+	requestOrderURI = "/requestOrder"
+	// authorizationNonceLength 随机串长度
+	authorizationNonceLength = 10
+)
+
+// ErrByteAuthorizationFormat byteAuthorization 格式不合法
+var ErrByteAuthorizationFormat = errors.New("trade: invalid byteAuthorization format")
+
+// ByteAuthorization tt.requestOrder 的 byteAuthorization 各组成部分
+type ByteAuthorization struct {
+	Scheme     string
+	AppID      string
+	NonceStr   string
+	Timestamp  string
+	KeyVersion string
+	Signature  string
+}
+
+// String 格式化为 SHA256-RSA2048 appid=…,nonce_str=…,timestamp=…,key_version=…,signature=…
+func (a *ByteAuthorization) String() string {
+	return fmt.Sprintf("%s appid=%s,nonce_str=%s,timestamp=%s,key_version=%s,signature=%s",
+		a.Scheme, a.AppID, a.NonceStr, a.Timestamp, a.KeyVersion, a.Signature)
+}
+
+// ParseByteAuthorization 解析 byteAuthorization，缺少任一组成部分时返回 ErrByteAuthorizationFormat
+func ParseByteAuthorization(s string) (*ByteAuthorization, error) {
+	scheme, params, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing scheme", ErrByteAuthorizationFormat)
+	}
+	a := &ByteAuthorization{Scheme: scheme}
+	fields := map[string]*string{
+		"appid":       &a.AppID,
+		"nonce_str":   &a.NonceStr,
+		"timestamp":   &a.Timestamp,
+		"key_version": &a.KeyVersion,
+		"signature":   &a.Signature,
+	}
+	for _, pair := range strings.Split(params, ",") {
+		// signature 为 base64，可能包含 '='，只按第一个 '=' 切分
+		key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: malformed pair %q", ErrByteAuthorizationFormat, pair)
+		}
+		if field, ok := fields[key]; ok {
+			*field = val
+		}
+	}
+	for key, field := range fields {
+		if *field == "" {
+			return nil, fmt.Errorf("%w: missing %s", ErrByteAuthorizationFormat, key)
+		}
+	}
+	return a, nil
+}
+
+// AuthorizationSigner byteAuthorization 签名参数，Now 与 Nonce 为空时使用当前时间与随机串
+type AuthorizationSigner struct {
+	PrivateKey string // base64 编码的私钥，与 config.WithPrivateKey 相同
+	KeyType    config.Secret
+	AppID      string
+	KeyVersion string
+	Now        func() time.Time
+	Nonce      func() string
+}
+
+// BuildByteAuthorization 对 data 签名生成 byteAuthorization，注入固定的 Now 与 Nonce 时结果可复现
+func BuildByteAuthorization(signer AuthorizationSigner, data string) (*ByteAuthorization, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(signer.PrivateKey, "\n", ""))
+	if err != nil {
+		return nil, err
+	}
+	now, nonce := signer.Now, signer.Nonce
+	if now == nil {
+		now = time.Now
+	}
+	if nonce == nil {
+		nonce = func() string {
+			return helper.RandomStr(authorizationNonceLength)
+		}
+	}
+	a := &ByteAuthorization{
+		Scheme:     authorizationScheme,
+		AppID:      signer.AppID,
+		NonceStr:   nonce(),
+		Timestamp:  strconv.FormatInt(now().Unix(), 10),
+		KeyVersion: signer.KeyVersion,
+	}
+	if a.Signature, err = helper.GenSign(requestOrderMethod, requestOrderURI, a.Timestamp, a.NonceStr, data, key, signer.KeyType); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// AuthorizationMismatch byteAuthorization 中与期望不一致的组成部分
+type AuthorizationMismatch struct {
+	Field string
+	Got   string
+	Want  string
+}
+
+// AuthorizationError byteAuthorization 校验失败，列出全部不一致的组成部分
+type AuthorizationError struct {
+	Mismatches []AuthorizationMismatch
+}
+
+// Error implements error
+func (e *AuthorizationError) Error() string {
+	parts := make([]string, 0, len(e.Mismatches))
+	for _, m := range e.Mismatches {
+		parts = append(parts, fmt.Sprintf("%s: got %q, want %q", m.Field, m.Got, m.Want))
+	}
+	return "trade: byteAuthorization mismatch: " + strings.Join(parts, "; ")
+}
+
+// VerifyByteAuthorization 校验 byteAuthorization：scheme、appid、key_version 与期望一致，
+// 且签名可由 publicKey（PEM 格式的开发者公钥）对 data 验证，不一致时返回 *AuthorizationError
+func VerifyByteAuthorization(header, data, appID, keyVersion, publicKey string) error {
+	a, err := ParseByteAuthorization(header)
+	if err != nil {
+		return err
+	}
+	pubKey, err := helper.PemToRSAPublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	var mismatches []AuthorizationMismatch
+	for _, c := range []AuthorizationMismatch{
+		{Field: "scheme", Got: a.Scheme, Want: authorizationScheme},
+		{Field: "appid", Got: a.AppID, Want: appID},
+		{Field: "key_version", Got: a.KeyVersion, Want: keyVersion},
+	} {
+		if c.Got != c.Want {
+			mismatches = append(mismatches, c)
+		}
+	}
+
+	hashed := sha256.Sum256([]byte(requestOrderMethod + "\n" + requestOrderURI + "\n" + a.Timestamp + "\n" + a.NonceStr + "\n" + data + "\n"))
+	sign, err := base64.StdEncoding.DecodeString(a.Signature)
+	if err == nil {
+		err = rsa.VerifyPKCS1v15(pubKey, crypto.SHA256, hashed[:], sign)
+	}
+	if err != nil {
+		mismatches = append(mismatches, AuthorizationMismatch{Field: "signature", Got: a.Signature, Want: "signature of data with timestamp " + a.Timestamp + " and nonce_str " + a.NonceStr})
+	}
+
+	if len(mismatches) > 0 {
+		return &AuthorizationError{Mismatches: mismatches}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package trade
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/houseme/bytedance/config"
+)
+
+func TestByteAuthorization(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	pub, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}))
+
+	signer := AuthorizationSigner{
+		PrivateKey: base64.StdEncoding.EncodeToString(der),
+		KeyType:    config.PKCS8,
+		AppID:      "tt-app",
+		KeyVersion: "1",
+		Now:        func() time.Time { return time.Unix(1700000000, 0) },
+		Nonce:      func() string { return "nonce12345" },
+	}
+	data := `{"outOrderNo":"out-1"}`
+	first, err := BuildByteAuthorization(signer, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := BuildByteAuthorization(signer, data)
+	if first.String() != second.String() {
+		t.Errorf("BuildByteAuthorization() is not reproducible:\n%s\n%s", first, second)
+	}
+
+	parsed, err := ParseByteAuthorization(first.String())
+	if err != nil || *parsed != *first {
+		t.Fatalf("ParseByteAuthorization() = %+v, %v, want %+v", parsed, err, first)
+	}
+	if _, err = ParseByteAuthorization("SHA256-RSA2048 appid=tt-app"); !errors.Is(err, ErrByteAuthorizationFormat) {
+		t.Errorf("ParseByteAuthorization() incomplete err = %v", err)
+	}
+
+	if err = VerifyByteAuthorization(first.String(), data, "tt-app", "1", publicKey); err != nil {
+		t.Fatalf("VerifyByteAuthorization() = %v", err)
+	}
+	var authErr *AuthorizationError
+	err = VerifyByteAuthorization(first.String(), `{"outOrderNo":"out-2"}`, "tt-other", "1", publicKey)
+	if !errors.As(err, &authErr) || len(authErr.Mismatches) != 2 ||
+		authErr.Mismatches[0].Field != "appid" || authErr.Mismatches[1].Field != "signature" {
+		t.Errorf("VerifyByteAuthorization() tampered = %v, want appid and signature mismatches", err)
+	}
+}
@@ -40,6 +40,23 @@ const (
     GeneralConsultationNoRefundTagID = "tag_group_7272625659888025612"
 )
 
+const (
+    // GoodsTypeNumberCard 商品类型：号卡商品
+    GoodsTypeNumberCard = 101
+    // GoodsTypeCustomized 商品类型：通信定制类商品（彩铃）
+    GoodsTypeCustomized = 102
+    // GoodsTypeRecharge 商品类型：话费/宽带充值类商品
+    GoodsTypeRecharge = 103
+    // GoodsTypeConsultation 商品类型：通用咨询类商品
+    GoodsTypeConsultation = 201
+    // GoodsTypeGhostwriting 商品类型：代写文书
+    GoodsTypeGhostwriting = 202
+    // GoodsTypeVirtualTool 商品类型：虚拟工具类商品
+    GoodsTypeVirtualTool = 301
+    // GoodsTypeContent 商品类型：内容消费类商品
+    GoodsTypeContent = 401
+)
+
 const (
     // queryTag query tag https://open.douyin.com/api/trade_basic/v1/developer/tag_query/
     // see: https://developer.open-douyin.com/docs/resource/zh-CN/mini-app/develop/server/trade-system/general/tag/tag_group_query
@@ -53,4 +70,40 @@ const (
 const (
     // 支付超时时间，单位秒 默认值 300
     defaultPayExpireSeconds = 300
+    // 支付超时时间上限 48 小时
+    maxPayExpireSeconds = 48 * 60 * 60
+    // 开发者订单号最大长度，单位 byte
+    maxOutOrderNoLength = 64
+    // 链接最大长度，单位 byte
+    maxURLLength = 512
+    // 单个商品最大购买数量
+    maxSkuQuantity = 100
+)
+
+const (
+    // PayWayWeChat 支付渠道：微信
+    PayWayWeChat = 1
+    // PayWayAlipay 支付渠道：支付宝
+    PayWayAlipay = 2
+    // PayWayDouyin 支付渠道：抖音支付
+    PayWayDouyin = 10
+)
+
+const (
+    // AsyncPreCreateOrder 预下单回调类型
+    AsyncPreCreateOrder = "pre_create_order"
+    
+    // preCreateOrderVersion 预下单回调版本号
+    preCreateOrderVersion = "3.0"
+    
+    // ErrNoPreCreateSuccess 预下单回调应答：接受下单
+    ErrNoPreCreateSuccess = 0
+    // ErrNoPreCreateRejected 预下单回调应答：业务拒绝下单，err_tips 会展示给用户
+    ErrNoPreCreateRejected = 1
+    // ErrNoPreCreateSignature 预下单回调应答：验签失败
+    ErrNoPreCreateSignature = 400
+    // ErrNoPreCreateParameter 预下单回调应答：请求参数错误
+    ErrNoPreCreateParameter = 401
+    // ErrNoPreCreateSystem 预下单回调应答：系统错误，平台会重试
+    ErrNoPreCreateSystem = 10000
 )
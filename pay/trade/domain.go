@@ -19,6 +19,8 @@
 
 package trade
 
+import "github.com/houseme/bytedance/utility/helper"
+
 // QueryOrderRequest 查询订单
 type QueryOrderRequest struct {
     OrderID    string `json:"order_id,omitempty" description:"交易订单号，order_id 与 out_order_no 二选一"`
@@ -92,3 +94,70 @@ type SkuItem struct {
     TagGroupID  string   `json:"tagGroupId" description:"商品标签组 id，用于商品标签的分组，长度 <= 64 字节"`
     EntrySchema *Schema  `json:"entrySchema,omitempty" description:"商品详情页 schema，用于描述商品详情页的跳转协议"`
 }
+
+// PreCreateOrderData 预下单回调信息
+type PreCreateOrderData struct {
+    AppID           string            `json:"app_id" description:"小程序 app_id"`
+    OpenID          string            `json:"open_id" description:"下单用户的 open_id"`
+    UnionID         string            `json:"union_id" description:"下单用户的 union_id"`
+    OrderID         string            `json:"order_id" description:"抖音开平侧订单号"`
+    TotalAmount     int               `json:"total_amount" description:"订单总金额，单位分"`
+    DiscountAmount  int               `json:"discount_amount" description:"订单优惠金额，单位分"`
+    Goods           []*PreCreateGoods `json:"goods" description:"下单商品信息"`
+    Phone           string            `json:"phone" description:"下单用户手机号"`
+    CpExtra         string            `json:"cp_extra" description:"开发者自定义透传字段"`
+    CreateOrderTime int64             `json:"create_order_time" description:"下单时间，13 位毫秒时间戳"`
+    
+    claim *helper.ReplayClaim
+}
+
+// PreCreateGoods 预下单商品信息
+type PreCreateGoods struct {
+    GoodsID        string `json:"goods_id" description:"商品 id"`
+    GoodsType      int    `json:"goods_type" description:"商品类型"`
+    Quantity       int    `json:"quantity" description:"购买数量"`
+    Price          int    `json:"price" description:"商品单价，单位分"`
+    TotalAmount    int    `json:"total_amount" description:"商品总金额，单位分"`
+    DiscountAmount int    `json:"discount_amount" description:"商品优惠金额，单位分"`
+}
+
+// PreCreateOrderResponse 预下单回调应答
+type PreCreateOrderResponse struct {
+    ErrNo   int                   `json:"err_no"`
+    ErrTips string                `json:"err_tips"`
+    Data    *PreCreateOrderResult `json:"data,omitempty"`
+}
+
+// PreCreateOrderResult 预下单回调应答 data，接受下单时返回
+type PreCreateOrderResult struct {
+    OutOrderNo       string  `json:"out_order_no" description:"开发者侧订单号，长度 <= 64byte"`
+    OrderEntrySchema *Schema `json:"order_entry_schema,omitempty" description:"订单详情页 schema"`
+    OrderValidTime   int64   `json:"order_valid_time,omitempty" description:"订单有效时间，单位毫秒，超时未支付将自动关单"`
+    PayNotifyURL     string  `json:"pay_notify_url,omitempty" description:"支付结果通知地址，不传使用小程序配置的回调地址"`
+    MerchantUID      string  `json:"merchant_uid,omitempty" description:"该笔交易卖家商户号"`
+}
+
+// QueryTagRequest 查询标签组信息
+type QueryTagRequest struct {
+    GoodsType int `json:"goods_type" description:"商品类型，如 101：号卡商品，401：内容消费类商品"`
+}
+
+// QueryTagResponse 查询标签组信息
+type QueryTagResponse struct {
+    Data   *QueryTagData `json:"data"`
+    ErrNo  int           `json:"err_no"`
+    ErrMsg string        `json:"err_msg"`
+    LogID  string        `json:"log_id"`
+}
+
+// QueryTagData 查询标签组信息
+type QueryTagData struct {
+    TagList []*TagGroup `json:"tag_list"`
+}
+
+// TagGroup 标签组
+type TagGroup struct {
+    TagGroupID   string `json:"tag_group_id" description:"标签组 id，下单时作为 SkuItem.TagGroupID 传入"`
+    TagGroupName string `json:"tag_group_name" description:"标签组名称"`
+    TagGroupDesc string `json:"tag_group_desc" description:"标签组描述，如退款规则"`
+}
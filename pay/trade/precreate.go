@@ -0,0 +1,127 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package trade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/houseme/bytedance/pay/asyncnotify"
+	"github.com/houseme/bytedance/utility/base"
+	"github.com/houseme/bytedance/utility/helper"
+)
+
+var (
+	// ErrPreCreateOrderSignature 预下单回调验签失败
+	ErrPreCreateOrderSignature = errors.New("trade: pre-create order callback signature check failed")
+	// ErrPreCreateOrderRequest 预下单回调请求不合法
+	ErrPreCreateOrderRequest = errors.New("trade: invalid pre-create order callback")
+)
+
+// ConfirmOrderFunc 预下单确认函数，返回 AcceptPreCreateOrder 或 RejectPreCreateOrder 的结果，返回 error 时应答系统错误，平台会重试
+type ConfirmOrderFunc func(ctx context.Context, data *PreCreateOrderData) (*PreCreateOrderResponse, error)
+
+// VerifyPreCreateOrder 校验预下单回调的版本、类型、签名与防重放，通过后解析回调信息，处理失败时调用 data.Finish 释放 nonce 以便平台重试
+func (t *Trade) VerifyPreCreateOrder(ctx context.Context, req *asyncnotify.AsyncRequest) (*PreCreateOrderData, error) {
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
+	}
+	if req.Version != preCreateOrderVersion || req.Type != AsyncPreCreateOrder {
+		return nil, fmt.Errorf("%w: version %q, type %q", ErrPreCreateOrderRequest, req.Version, req.Type)
+	}
+
+	cfg := t.ctxCfg.Config
+	ok, err := helper.CheckSign(req.ByteTimestamp, req.ByteNonceStr, req.Content, req.ByteSignature, cfg.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPreCreateOrderSignature
+	}
+	claim, err := t.ctxCfg.ReplayGuard().Check(ctx, req.ByteTimestamp, req.ByteNonceStr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPreCreateOrderSignature, err)
+	}
+
+	data := &PreCreateOrderData{claim: claim}
+	if err = json.Unmarshal([]byte(req.Msg), data); err != nil {
+		return nil, claim.Finish(ctx, fmt.Errorf("%w: %w", ErrPreCreateOrderRequest, err))
+	}
+	return data, nil
+}
+
+// Finish 确认下单结束后调用：回调 nonce 已在校验时记录，handleErr 非 nil 时释放以便平台重试；返回 handleErr 与释放 nonce 的错误
+func (d *PreCreateOrderData) Finish(ctx context.Context, handleErr error) error {
+	return d.claim.Finish(ctx, handleErr)
+}
+
+// AcceptPreCreateOrder 接受下单，outOrderNo 为开发者侧订单号
+func AcceptPreCreateOrder(outOrderNo string, schema *Schema) *PreCreateOrderResponse {
+	return &PreCreateOrderResponse{
+		ErrNo:   ErrNoPreCreateSuccess,
+		ErrTips: "success",
+		Data:    &PreCreateOrderResult{OutOrderNo: outOrderNo, OrderEntrySchema: schema},
+	}
+}
+
+// RejectPreCreateOrder 拒绝下单，reason 会展示给用户，例如库存不足
+func RejectPreCreateOrder(reason string) *PreCreateOrderResponse {
+	return &PreCreateOrderResponse{ErrNo: ErrNoPreCreateRejected, ErrTips: reason}
+}
+
+// PreCreateOrderHandler 预下单回调的 http.Handler，验签通过后调用 confirm 并将其结果写入应答
+func PreCreateOrderHandler(t ITrade, confirm ConfirmOrderFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		req, err := asyncnotify.ParseRequest(r)
+		if err != nil {
+			writePreCreateOrder(w, &PreCreateOrderResponse{ErrNo: ErrNoPreCreateParameter, ErrTips: err.Error()})
+			return
+		}
+		data, err := t.VerifyPreCreateOrder(ctx, req)
+		switch {
+		case errors.Is(err, ErrPreCreateOrderSignature):
+			writePreCreateOrder(w, &PreCreateOrderResponse{ErrNo: ErrNoPreCreateSignature, ErrTips: err.Error()})
+			return
+		case err != nil:
+			writePreCreateOrder(w, &PreCreateOrderResponse{ErrNo: ErrNoPreCreateParameter, ErrTips: err.Error()})
+			return
+		}
+
+		resp, err := confirm(ctx, data)
+		if err == nil && (resp == nil || (resp.ErrNo == ErrNoPreCreateSuccess && (resp.Data == nil || resp.Data.OutOrderNo == ""))) {
+			err = errors.New("out_order_no is empty")
+		}
+		if err = data.Finish(ctx, err); err != nil {
+			resp = &PreCreateOrderResponse{ErrNo: ErrNoPreCreateSystem, ErrTips: err.Error()}
+		}
+		writePreCreateOrder(w, resp)
+	})
+}
+
+// writePreCreateOrder 写入预下单回调应答
+func writePreCreateOrder(w http.ResponseWriter, resp *PreCreateOrderResponse) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
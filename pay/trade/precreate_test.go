@@ -0,0 +1,81 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package trade_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/credential"
+	"github.com/houseme/bytedance/pay/trade"
+	"github.com/houseme/bytedance/utility/callbacksim"
+)
+
+func TestPreCreateOrderHandler(t *testing.T) {
+	sim, err := callbacksim.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	ctxCfg := &credential.ContextConfig{Config: config.New(ctx, config.WithPublicKey(sim.PublicKey()))}
+	handler := trade.PreCreateOrderHandler(trade.NewTrade(ctxCfg), func(_ context.Context, data *trade.PreCreateOrderData) (*trade.PreCreateOrderResponse, error) {
+		if data.TotalAmount > 1000 {
+			return trade.RejectPreCreateOrder("库存不足"), nil
+		}
+		return trade.AcceptPreCreateOrder("out-"+data.OrderID, &trade.Schema{Path: "pages/order"}), nil
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	cases := []struct {
+		amount     int
+		errNo      int
+		outOrderNo string
+	}{
+		{500, trade.ErrNoPreCreateSuccess, "out-ord-1"},
+		{5000, trade.ErrNoPreCreateRejected, ""},
+	}
+	for _, c := range cases {
+		req, _ := sim.PreCreateOrder(&trade.PreCreateOrderData{OrderID: "ord-1", TotalAmount: c.amount})
+		ack, err := sim.DeliverPay(ctx, server.URL, req)
+		if err != nil || ack.ErrNo != c.errNo {
+			t.Fatalf("pre-create ack = %+v, %v, want err_no %d", ack, err, c.errNo)
+		}
+		var resp trade.PreCreateOrderResponse
+		_ = json.Unmarshal(ack.Body, &resp)
+		var got string
+		if resp.Data != nil {
+			got = resp.Data.OutOrderNo
+		}
+		if got != c.outOrderNo {
+			t.Errorf("out_order_no = %q, want %q", got, c.outOrderNo)
+		}
+	}
+
+	req, _ := sim.PreCreateOrder(&trade.PreCreateOrderData{OrderID: "ord-2"})
+	req.Content = strings.Replace(req.Content, "ord-2", "forged", 1)
+	if ack, _ := sim.DeliverPay(ctx, server.URL, req); ack.ErrNo != trade.ErrNoPreCreateSignature {
+		t.Errorf("forged pre-create ack err_no = %d, want %d", ack.ErrNo, trade.ErrNoPreCreateSignature)
+	}
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package trade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/houseme/bytedance/utility/base"
+)
+
+// tagCatalogTTL 标签组目录的缓存时长
+const tagCatalogTTL = 24 * time.Hour
+
+// ErrSkuTagMismatch 商品类型与标签组不匹配
+var ErrSkuTagMismatch = errors.New("trade: sku type and tag group mismatch")
+
+// knownTagGoodsType constant.go 中已知标签组所属的商品类型
+var knownTagGoodsType = map[string]int{
+	NumberCardCommodityTagID:         GoodsTypeNumberCard,
+	CustomizedServiceRefundTagID:     GoodsTypeCustomized,
+	CustomizedServiceNoRefundTagID:   GoodsTypeCustomized,
+	VirtualRechargeTagID:             GoodsTypeRecharge,
+	GeneralConsultationRefundTagID:   GoodsTypeConsultation,
+	GeneralConsultationNoRefundTagID: GoodsTypeConsultation,
+	GhostwritingDocumentTagID:        GoodsTypeGhostwriting,
+	VirtualServiceTagID:              GoodsTypeVirtualTool,
+	ContentRechargeTagID:             GoodsTypeContent,
+}
+
+// QueryTag 查询商品类型可用的标签组
+func (t *Trade) QueryTag(ctx context.Context, req *QueryTagRequest) (resp *QueryTagResponse, err error) {
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
+	}
+	if req.GoodsType < 1 {
+		return nil, base.ErrParamKeyValueEmpty("GoodsType")
+	}
+
+	if ctx, err = t.setContext(ctx); err != nil {
+		return nil, err
+	}
+	var response []byte
+	if response, err = t.ctxCfg.Request().PostJSON(ctx, queryTag, req); err != nil {
+		return nil, err
+	}
+	resp = new(QueryTagResponse)
+	err = json.Unmarshal(response, resp)
+	return
+}
+
+// TagGroups 获取商品类型可用的标签组，优先读取缓存，未命中时调用 QueryTag 并缓存 tagCatalogTTL；
+// 空列表不缓存，避免平台偶发返回空时在缓存期内拒绝该类型的所有 sku
+func (t *Trade) TagGroups(ctx context.Context, goodsType int) ([]*TagGroup, error) {
+	if groups, ok := t.cachedTagGroups(ctx, goodsType); ok {
+		return groups, nil
+	}
+
+	resp, err := t.QueryTag(ctx, &QueryTagRequest{GoodsType: goodsType})
+	if err != nil {
+		return nil, err
+	}
+	if resp.ErrNo != 0 {
+		return nil, base.Error{ErrCode: resp.ErrNo, ErrMsg: resp.ErrMsg}
+	}
+	var groups []*TagGroup
+	if resp.Data != nil {
+		groups = resp.Data.TagList
+	}
+	if cache := t.ctxCfg.Cache(); cache != nil && len(groups) > 0 {
+		if val, err := json.Marshal(groups); err == nil {
+			_ = cache.Set(ctx, t.tagCacheKey(goodsType), string(val), tagCatalogTTL)
+		}
+	}
+	return groups, nil
+}
+
+// ValidateSkuTags 校验每个 sku 的标签组属于其商品类型可用的标签组，缓存未命中时会调用 QueryTag。
+// CreateTrade 只做不发起网络请求的校验，需要以平台目录为准时在下单前显式调用
+func (t *Trade) ValidateSkuTags(ctx context.Context, skus []*SkuItem) error {
+	for _, sku := range skus {
+		if err := checkSkuTag(sku); err != nil {
+			return err
+		}
+		groups, err := t.TagGroups(ctx, sku.Type)
+		if err != nil {
+			return err
+		}
+		if err = matchTagGroups(sku, groups); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSkuTags CreateTrade 签名前的本地校验：constant.go 中已知的标签组必须属于 sku 的商品类型，
+// 缓存中已有该商品类型的标签组目录时同时按目录校验，不发起网络请求
+func (t *Trade) checkSkuTags(ctx context.Context, skus []*SkuItem) error {
+	for _, sku := range skus {
+		if err := checkSkuTag(sku); err != nil {
+			return err
+		}
+		if groups, ok := t.cachedTagGroups(ctx, sku.Type); ok {
+			if err := matchTagGroups(sku, groups); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cachedTagGroups 读取缓存的标签组目录
+func (t *Trade) cachedTagGroups(ctx context.Context, goodsType int) ([]*TagGroup, bool) {
+	cache := t.ctxCfg.Cache()
+	if cache == nil {
+		return nil, false
+	}
+	val, ok := cache.Get(ctx, t.tagCacheKey(goodsType)).(string)
+	if !ok || val == "" {
+		return nil, false
+	}
+	var groups []*TagGroup
+	if err := json.Unmarshal([]byte(val), &groups); err != nil {
+		return nil, false
+	}
+	return groups, true
+}
+
+// tagCacheKey 标签组目录的缓存 key
+func (t *Trade) tagCacheKey(goodsType int) string {
+	return t.ctxCfg.CacheKeyPrefix() + "_trade_tag_" + strconv.Itoa(goodsType)
+}
+
+// checkSkuTag 校验 sku 设置了标签组，且已知标签组与商品类型匹配
+func checkSkuTag(sku *SkuItem) error {
+	if sku == nil {
+		return base.ErrParamKeyValueEmpty("SkuList")
+	}
+	if sku.TagGroupID == "" {
+		return base.ErrParamKeyValueEmpty("TagGroupID")
+	}
+	if goodsType, ok := knownTagGoodsType[sku.TagGroupID]; ok && goodsType != sku.Type {
+		return fmt.Errorf("%w: sku %s type %d does not allow tag group %s", ErrSkuTagMismatch, sku.SkuID, sku.Type, sku.TagGroupID)
+	}
+	return nil
+}
+
+// matchTagGroups 校验 sku 的标签组在目录中
+func matchTagGroups(sku *SkuItem, groups []*TagGroup) error {
+	for _, group := range groups {
+		if group.TagGroupID == sku.TagGroupID {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: sku %s type %d does not allow tag group %s", ErrSkuTagMismatch, sku.SkuID, sku.Type, sku.TagGroupID)
+}
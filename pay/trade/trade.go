@@ -22,18 +22,34 @@ package trade
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
-	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/houseme/bytedance/config"
 	"github.com/houseme/bytedance/credential"
+	"github.com/houseme/bytedance/pay/asyncnotify"
 	"github.com/houseme/bytedance/utility/base"
-	"github.com/houseme/bytedance/utility/helper"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type ITrade -impl Trade
+
+// ITrade Trade 服务接口，便于替换为 FakeTrade 等测试替身
+type ITrade interface {
+	// QueryTrade query trade relation
+	QueryTrade(ctx context.Context, req *QueryOrderRequest) (*QueryOrderResponse, error)
+	// CreateTrade create trade relation
+	CreateTrade(ctx context.Context, req *CreateOrderRequest) (*CreateOrderResponse, error)
+	// QueryTag 查询商品类型可用的标签组
+	QueryTag(ctx context.Context, req *QueryTagRequest) (*QueryTagResponse, error)
+	// TagGroups 获取商品类型可用的标签组，带缓存
+	TagGroups(ctx context.Context, goodsType int) ([]*TagGroup, error)
+	// ValidateSkuTags 按平台标签组目录校验 sku 的商品类型与标签组，可在 CreateTrade 前调用
+	ValidateSkuTags(ctx context.Context, skus []*SkuItem) error
+	// VerifyPreCreateOrder 校验并解析预下单回调
+	VerifyPreCreateOrder(ctx context.Context, req *asyncnotify.AsyncRequest) (*PreCreateOrderData, error)
+}
+
 // Trade creates trade relation
 type Trade struct {
 	ctxCfg *credential.ContextConfig
@@ -92,23 +108,19 @@ func (t *Trade) QueryTrade(ctx context.Context, req *QueryOrderRequest) (resp *Q
 	return
 }
 
-// CreateTrade create trade relation
+// CreateTrade create trade relation，仅在本地校验并签名，不发起网络请求；
+// 签名前校验 sku 的商品类型与标签组，见 checkSkuTags
 func (t *Trade) CreateTrade(ctx context.Context, req *CreateOrderRequest) (resp *CreateOrderResponse, err error) {
 	t.ctxCfg.Logger().Debug(ctx, "CreatePay req:", req)
-	if req.OutOrderNo == "" {
-		return nil, base.ErrParamKeyValueEmpty("OutOrderNo")
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
 	}
 
-	if req.TotalAmount < 1 {
-		return nil, base.ErrParamKeyValueEmpty("TotalAmount")
-	}
-
-	if req.SkuList == nil || len(req.SkuList) < 1 {
-		return nil, base.ErrParamKeyValueEmpty("SkuList")
+	if err = req.Validate(); err != nil {
+		return nil, err
 	}
-
-	if req.OrderEntrySchema == nil {
-		return nil, base.ErrParamKeyValueEmpty("OrderEntrySchema")
+	if err = t.checkSkuTags(ctx, req.SkuList); err != nil {
+		return nil, err
 	}
 
 	if req.PayExpireSeconds < 1 {
@@ -119,28 +131,17 @@ func (t *Trade) CreateTrade(ctx context.Context, req *CreateOrderRequest) (resp
 		return
 	}
 	resp = &CreateOrderResponse{
-		Data:              string(reqByte),
-		ByteAuthorization: "",
-	}
-	if resp.ByteAuthorization, err = t.getByteAuthorization(t.ctxCfg.PrivateKey(), resp.Data, t.ctxCfg.ClientKey(), helper.RandomStr(10), strconv.FormatInt(helper.GetCurrTS(), 10), strconv.Itoa(t.ctxCfg.KeyVersion())); err != nil {
+		Data: string(reqByte),
+	}
+	var auth *ByteAuthorization
+	if auth, err = BuildByteAuthorization(AuthorizationSigner{
+		PrivateKey: t.ctxCfg.PrivateKey(),
+		KeyType:    t.ctxCfg.KeyType(),
+		AppID:      t.ctxCfg.ClientKey(),
+		KeyVersion: strconv.Itoa(t.ctxCfg.KeyVersion()),
+	}, resp.Data); err != nil {
 		return
 	}
+	resp.ByteAuthorization = auth.String()
 	return
 }
-
-func (t *Trade) getByteAuthorization(privateKeyStr, data, appId, nonceStr, timestamp, keyVersion string) (string, error) {
-	// 读取私钥
-	key, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(privateKeyStr, "\n", ""))
-	if err != nil {
-		return "", err
-	}
-
-	// 生成签名
-	signature, err := helper.GenSign("POST", "/requestOrder", timestamp, nonceStr, data, key, t.ctxCfg.KeyType())
-	if err != nil {
-		return "", err
-	}
-	// 构造 byteAuthorization
-	byteAuthorization := fmt.Sprintf("SHA256-RSA2048 appid=%s,nonce_str=%s,timestamp=%s,key_version=%s,signature=%s", appId, nonceStr, timestamp, keyVersion, signature)
-	return byteAuthorization, nil
-}
@@ -0,0 +1,90 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package trade
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/credential"
+	"github.com/houseme/bytedance/utility/base"
+	"github.com/houseme/bytedance/utility/cache"
+)
+
+func TestCreateTradeSkuTags(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	ctx := context.Background()
+	memory := cache.NewMemory()
+	tr := NewTrade(&credential.ContextConfig{Config: config.New(ctx,
+		config.WithCache(memory),
+		config.WithClientKey("tt-app"),
+		config.WithPrivateKey(base64.StdEncoding.EncodeToString(der)),
+		config.WithKeyType(config.PKCS8),
+	)})
+	order := func(goodsType int, tagGroupID string) *CreateOrderRequest {
+		return &CreateOrderRequest{
+			OutOrderNo:       "out-1",
+			TotalAmount:      100,
+			OrderEntrySchema: &Schema{Path: "pages/order"},
+			SkuList:          []*SkuItem{{SkuID: "sku-1", Price: 100, Quantity: 1, Type: goodsType, TagGroupID: tagGroupID}},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		req   *CreateOrderRequest
+		cache bool
+		want  error
+	}{
+		{name: "known tag of another type", req: order(GoodsTypeContent, VirtualServiceTagID), want: ErrSkuTagMismatch},
+		{name: "known tag", req: order(GoodsTypeContent, ContentRechargeTagID)},
+		{name: "unknown tag without catalog", req: order(GoodsTypeContent, "tag_group_custom")},
+		{name: "unknown tag not in cached catalog", req: order(GoodsTypeContent, "tag_group_custom"), cache: true, want: ErrSkuTagMismatch},
+		{name: "missing tag", req: order(GoodsTypeContent, ""), want: base.ErrParamKeyValueEmpty("TagGroupID")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.cache {
+				_ = memory.Set(ctx, tr.tagCacheKey(GoodsTypeContent), `[{"tag_group_id":"`+ContentRechargeTagID+`"}]`, time.Minute)
+			}
+			resp, err := tr.CreateTrade(ctx, tt.req)
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("CreateTrade() err = %v, want %v", err, tt.want)
+				}
+				return
+			}
+			if err != nil || resp.ByteAuthorization == "" {
+				t.Fatalf("CreateTrade() = %+v, %v", resp, err)
+			}
+		})
+	}
+}
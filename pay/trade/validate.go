@@ -0,0 +1,117 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package trade
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/houseme/bytedance/pay/money"
+)
+
+// ValidationError 请求参数校验错误，包含全部不合法的字段
+type ValidationError struct {
+	Violations []string
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	return "trade: invalid request: " + strings.Join(e.Violations, "; ")
+}
+
+// add 记录一条不合法的字段
+func (e *ValidationError) add(format string, args ...any) {
+	e.Violations = append(e.Violations, fmt.Sprintf(format, args...))
+}
+
+// err 没有不合法的字段时返回 nil
+func (e *ValidationError) err() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate 按文档约束校验下单请求，一次返回全部不合法的字段
+func (r *CreateOrderRequest) Validate() error {
+	v := &ValidationError{}
+	switch {
+	case r.OutOrderNo == "":
+		v.add("outOrderNo is empty")
+	case len(r.OutOrderNo) > maxOutOrderNoLength:
+		v.add("outOrderNo exceeds %d bytes", maxOutOrderNoLength)
+	}
+	if r.TotalAmount < 1 {
+		v.add("totalAmount must be positive")
+	}
+	if r.PayExpireSeconds < 0 || r.PayExpireSeconds > maxPayExpireSeconds {
+		v.add("payExpireSeconds must be between 0 and %d", maxPayExpireSeconds)
+	}
+	if len(r.PayNotifyURL) > maxURLLength {
+		v.add("payNotifyUrl exceeds %d bytes", maxURLLength)
+	}
+	if r.OrderEntrySchema == nil || r.OrderEntrySchema.Path == "" {
+		v.add("orderEntrySchema.path is empty")
+	}
+	for _, way := range r.LimitPayWayList {
+		if way != PayWayWeChat && way != PayWayAlipay && way != PayWayDouyin {
+			v.add("limitPayWayList contains unsupported pay way %d", way)
+		}
+	}
+
+	if len(r.SkuList) != 1 {
+		v.add("skuList must contain exactly one item, got %d", len(r.SkuList))
+	}
+	var total money.Money
+	for i, sku := range r.SkuList {
+		if sku == nil {
+			v.add("skuList[%d] is empty", i)
+			continue
+		}
+		if sku.SkuID == "" {
+			v.add("skuList[%d].skuId is empty", i)
+		}
+		if sku.Price < 1 {
+			v.add("skuList[%d].price must be positive", i)
+		}
+		if sku.Quantity < 1 || sku.Quantity > maxSkuQuantity {
+			v.add("skuList[%d].quantity must be between 1 and %d", i, maxSkuQuantity)
+		}
+		if len(sku.ImageList) > 1 {
+			v.add("skuList[%d].imageList supports at most one image", i)
+		}
+		for j, image := range sku.ImageList {
+			if len(image) > maxURLLength {
+				v.add("skuList[%d].imageList[%d] exceeds %d bytes", i, j, maxURLLength)
+			}
+		}
+		amount, err := money.FromCents(sku.Price).Mul(sku.Quantity)
+		if err == nil {
+			total, err = total.Add(amount)
+		}
+		if err != nil {
+			v.add("skuList[%d] amount overflows", i)
+		}
+	}
+	if len(r.SkuList) > 0 && r.TotalAmount >= 1 && total != money.FromCents(r.TotalAmount) {
+		v.add("totalAmount %d does not equal price × quantity %d", r.TotalAmount, total)
+	}
+	return v.err()
+}
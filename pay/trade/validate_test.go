@@ -0,0 +1,57 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package trade
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCreateOrderRequestValidate(t *testing.T) {
+	valid := &CreateOrderRequest{
+		OutOrderNo:       "out-1",
+		TotalAmount:      300,
+		OrderEntrySchema: &Schema{Path: "pages/order"},
+		LimitPayWayList:  []int{PayWayDouyin},
+		SkuList:          []*SkuItem{{SkuID: "sku-1", Price: 100, Quantity: 3, ImageList: []string{"https://img/1.png"}}},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() = %v, want nil", err)
+	}
+
+	invalid := &CreateOrderRequest{
+		OutOrderNo:       strings.Repeat("o", 65),
+		TotalAmount:      500,
+		PayExpireSeconds: maxPayExpireSeconds + 1,
+		LimitPayWayList:  []int{3},
+		SkuList: []*SkuItem{
+			{SkuID: "sku-1", Price: 100, Quantity: 101, ImageList: []string{"a", "b"}},
+			{SkuID: "sku-2", Price: 100, Quantity: 1},
+		},
+	}
+	var verr *ValidationError
+	if err := invalid.Validate(); !errors.As(err, &verr) {
+		t.Fatalf("Validate() = %v, want *ValidationError", err)
+	}
+	if len(verr.Violations) != 8 {
+		t.Errorf("Validate() violations = %q, want 8", verr.Violations)
+	}
+}
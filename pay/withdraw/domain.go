@@ -66,6 +66,7 @@ type MerchantWithdrawRequest struct {
     ChannelType    string `json:"channel_type"`
     WithdrawAmount int    `json:"withdraw_amount"`
     OutOrderID     string `json:"out_order_id"`
+    MerchantEntity int    `json:"merchant_entity,omitempty" description:"抖音信息和光合信号主体标识：不传或传 0 或 1 为抖音信息主体，传 2 为光合信号主体"`
 }
 
 // MerchantWithdrawResponse 商户提现
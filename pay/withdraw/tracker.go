@@ -0,0 +1,303 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package withdraw
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/houseme/bytedance/pay/asyncnotify"
+)
+
+const (
+	// EventApplied 提现已申请
+	EventApplied = "applied"
+	// EventSucceeded 提现成功
+	EventSucceeded = "succeeded"
+	// EventFailed 提现失败
+	EventFailed = "failed"
+	// EventReExchanged 退票，提现成功或处理中的资金被退回商户余额
+	EventReExchanged = "reexchanged"
+
+	// defaultInitialInterval 默认首次轮询间隔
+	defaultInitialInterval = 2 * time.Second
+	// defaultMaxInterval 默认最大轮询间隔
+	defaultMaxInterval = time.Minute
+)
+
+// ErrInsufficientBalance 提现金额超过可提现余额
+var ErrInsufficientBalance = errors.New("withdraw: amount exceeds withdrawable balance")
+
+// Record 提现单跟踪记录
+type Record struct {
+	Request   MerchantWithdrawRequest
+	OrderID   string
+	Status    string
+	StatusMsg string
+	UpdatedAt time.Time
+}
+
+// Event 提现状态变化事件
+type Event struct {
+	Type       string
+	PrevStatus string
+	Record     Record
+}
+
+// EventHandler 提现事件处理函数
+type EventHandler func(ctx context.Context, event *Event)
+
+// Tracker 提现跟踪：申请前校验余额，轮询或消费回调直到终态，并在 SUCCESS 之后继续识别 REEXCHANGE 退票
+type Tracker struct {
+	withdraw        IWithdraw
+	handler         EventHandler
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	now             func() time.Time
+
+	mu      sync.Mutex
+	records map[string]*Record
+}
+
+type trackerOptions struct {
+	handler         EventHandler
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	now             func() time.Time
+}
+
+// TrackerOption tracker option
+type TrackerOption func(*trackerOptions)
+
+// WithEventHandler 设置提现事件处理函数
+func WithEventHandler(h EventHandler) TrackerOption {
+	return func(o *trackerOptions) {
+		o.handler = h
+	}
+}
+
+// WithBackoff 设置轮询间隔，每次轮询后翻倍，不超过 max
+func WithBackoff(initial, max time.Duration) TrackerOption {
+	return func(o *trackerOptions) {
+		o.initialInterval = initial
+		o.maxInterval = max
+	}
+}
+
+// WithTrackerClock 设置时间来源
+func WithTrackerClock(now func() time.Time) TrackerOption {
+	return func(o *trackerOptions) {
+		o.now = now
+	}
+}
+
+// NewTracker 创建提现跟踪，w 通常为 pay.Pay.Withdraw()
+func NewTracker(w IWithdraw, opts ...TrackerOption) *Tracker {
+	op := trackerOptions{
+		initialInterval: defaultInitialInterval,
+		maxInterval:     defaultMaxInterval,
+		now:             time.Now,
+	}
+	for _, option := range opts {
+		option(&op)
+	}
+	if op.maxInterval < op.initialInterval {
+		op.maxInterval = op.initialInterval
+	}
+	return &Tracker{
+		withdraw:        w,
+		handler:         op.handler,
+		initialInterval: op.initialInterval,
+		maxInterval:     op.maxInterval,
+		now:             op.now,
+		records:         make(map[string]*Record),
+	}
+}
+
+// Apply 查询可提现余额，余额充足时申请提现并开始跟踪，余额不足时返回 ErrInsufficientBalance
+func (t *Tracker) Apply(ctx context.Context, req *MerchantWithdrawRequest) (*Record, error) {
+	if req == nil || req.OutOrderID == "" || req.WithdrawAmount <= 0 {
+		return nil, fmt.Errorf("withdraw: out_order_id and positive withdraw_amount are required")
+	}
+	balance, err := t.withdraw.QueryBalance(ctx, &QueryBalanceRequest{
+		ThirdPartyID:   req.ThirdPartyID,
+		AppID:          req.AppID,
+		MerchantUID:    req.MerchantUID,
+		ChannelType:    req.ChannelType,
+		MerchantEntity: req.MerchantEntity,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if balance.ErrNo != 0 || balance.Data == nil {
+		return nil, fmt.Errorf("withdraw query balance err_no: %d, err_msg: %s", balance.ErrNo, balance.ErrMsg)
+	}
+	if available := balance.Data.AccountInfo.WithdrawAbleBalance; req.WithdrawAmount > available {
+		return nil, fmt.Errorf("%w: apply %d, withdrawable %d", ErrInsufficientBalance, req.WithdrawAmount, available)
+	}
+
+	resp, err := t.withdraw.Apply(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.ErrNo != 0 {
+		return nil, fmt.Errorf("withdraw apply err_no: %d, err_msg: %s", resp.ErrNo, resp.ErrMsg)
+	}
+
+	t.mu.Lock()
+	record := &Record{Request: *req, Status: StateProcessing, UpdatedAt: t.now()}
+	if resp.Data != nil {
+		record.OrderID = resp.Data.OrderID
+	}
+	t.records[req.OutOrderID] = record
+	snapshot := *record
+	t.mu.Unlock()
+
+	t.emit(ctx, &Event{Type: EventApplied, Record: snapshot})
+	return &snapshot, nil
+}
+
+// Track 以指数退避轮询提现单直到 SUCCESS、FAIL 或 REEXCHANGE，ctx 结束时返回 ctx.Err()
+func (t *Tracker) Track(ctx context.Context, outOrderID string) (*Record, error) {
+	interval := t.initialInterval
+	for {
+		record, err := t.Refresh(ctx, outOrderID)
+		if err != nil {
+			return nil, err
+		}
+		if record.Status != StateProcessing {
+			return record, nil
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return record, ctx.Err()
+		case <-timer.C:
+		}
+		if interval *= 2; interval > t.maxInterval {
+			interval = t.maxInterval
+		}
+	}
+}
+
+// Refresh 查询一次提现单状态，可在 SUCCESS 之后定期调用以识别退票
+func (t *Tracker) Refresh(ctx context.Context, outOrderID string) (*Record, error) {
+	t.mu.Lock()
+	record, ok := t.records[outOrderID]
+	var req MerchantWithdrawRequest
+	if ok {
+		req = record.Request
+	}
+	t.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("withdraw: out_order_id %s is not tracked", outOrderID)
+	}
+
+	resp, err := t.withdraw.QueryWithdraw(ctx, &QueryMerchantWithdrawRequest{
+		ThirdPartyID: req.ThirdPartyID,
+		AppID:        req.AppID,
+		MerchantUID:  req.MerchantUID,
+		ChannelType:  req.ChannelType,
+		OutOrderID:   outOrderID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.ErrNo != 0 || resp.Data == nil {
+		return nil, fmt.Errorf("withdraw query err_no: %d, err_msg: %s", resp.ErrNo, resp.ErrMsg)
+	}
+	return t.update(ctx, outOrderID, resp.Data.Status, resp.Data.StatusMsg, nil), nil
+}
+
+// HandleCallback 处理提现结果回调，可直接注册到 webhook.Router.OnWithdraw；未跟踪的提现单会根据回调建立记录
+func (t *Tracker) HandleCallback(ctx context.Context, data *asyncnotify.WithdrawData) error {
+	if data == nil || data.OutOrderID == "" {
+		return errors.New("withdraw: callback out_order_id is empty")
+	}
+	t.update(ctx, data.OutOrderID, data.Status, data.Message, data)
+	return nil
+}
+
+// Record 获取提现单的跟踪记录
+func (t *Tracker) Record(outOrderID string) (Record, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	record, ok := t.records[outOrderID]
+	if !ok {
+		return Record{}, false
+	}
+	return *record, true
+}
+
+// update 更新状态并在状态变化时发出事件；FAIL 与 REEXCHANGE 为终态，SUCCESS 之后只接受 REEXCHANGE
+func (t *Tracker) update(ctx context.Context, outOrderID, status, msg string, data *asyncnotify.WithdrawData) *Record {
+	t.mu.Lock()
+	record, ok := t.records[outOrderID]
+	if !ok {
+		record = &Record{Request: MerchantWithdrawRequest{OutOrderID: outOrderID}, Status: StateProcessing}
+		t.records[outOrderID] = record
+	}
+	if data != nil {
+		if record.OrderID == "" {
+			record.OrderID = data.OrderID
+		}
+		if record.Request.MerchantUID == "" {
+			record.Request.MerchantUID = data.MerchantUID
+			record.Request.ChannelType = data.ChannelType
+			record.Request.WithdrawAmount = data.WithdrawAmount
+		}
+	}
+
+	prev := record.Status
+	var event string
+	switch {
+	case status == prev || prev == StateFail || prev == StateReExchange:
+	case status == StateReExchange:
+		event = EventReExchanged
+	case prev == StateSuccess:
+	case status == StateSuccess:
+		event = EventSucceeded
+	case status == StateFail:
+		event = EventFailed
+	}
+	if event != "" {
+		record.Status = status
+		record.StatusMsg = msg
+		record.UpdatedAt = t.now()
+	}
+	snapshot := *record
+	t.mu.Unlock()
+
+	if event != "" {
+		t.emit(ctx, &Event{Type: event, PrevStatus: prev, Record: snapshot})
+	}
+	return &snapshot
+}
+
+// emit 发出事件
+func (t *Tracker) emit(ctx context.Context, event *Event) {
+	if t.handler != nil {
+		t.handler(ctx, event)
+	}
+}
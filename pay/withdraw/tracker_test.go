@@ -0,0 +1,97 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package withdraw_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/pay"
+	"github.com/houseme/bytedance/pay/asyncnotify"
+	"github.com/houseme/bytedance/pay/withdraw"
+	"github.com/houseme/bytedance/utility/cache"
+	"github.com/houseme/bytedance/utility/fakeserver"
+)
+
+func TestTracker(t *testing.T) {
+	srv := fakeserver.New()
+	defer srv.Close()
+
+	ctx := context.Background()
+	cfg := config.New(ctx,
+		config.WithBaseURL(srv.URL),
+		config.WithCache(cache.NewMemory()),
+		config.WithClientKey("tt-fake"),
+		config.WithClientSecret("secret"),
+		config.WithSalt("salt"),
+		config.WithToken("token"),
+		config.WithPublicKey("public"),
+		config.WithPrivateKey("private"),
+	)
+	p, err := pay.NewPay(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []string
+	tracker := withdraw.NewTracker(p.Withdraw(),
+		withdraw.WithBackoff(time.Millisecond, 4*time.Millisecond),
+		withdraw.WithEventHandler(func(_ context.Context, e *withdraw.Event) {
+			events = append(events, e.Type)
+		}),
+	)
+	srv.SetBalance("m-1", withdraw.Alipay, withdraw.AccountInfo{WithdrawAbleBalance: 500})
+
+	req := &withdraw.MerchantWithdrawRequest{MerchantUID: "m-1", ChannelType: withdraw.Alipay, WithdrawAmount: 800, OutOrderID: "wd-1"}
+	if _, err = tracker.Apply(ctx, req); !errors.Is(err, withdraw.ErrInsufficientBalance) {
+		t.Fatalf("Apply() over balance err = %v, want ErrInsufficientBalance", err)
+	}
+	req.WithdrawAmount = 300
+	if _, err = tracker.Apply(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	record, err := tracker.Track(ctx, "wd-1")
+	if err != nil || record.Status != withdraw.StateSuccess {
+		t.Fatalf("Track() = %+v, %v", record, err)
+	}
+
+	srv.SetWithdrawStatus("wd-1", withdraw.StateReExchange)
+	if record, err = tracker.Refresh(ctx, "wd-1"); err != nil || record.Status != withdraw.StateReExchange {
+		t.Fatalf("Refresh() = %+v, %v", record, err)
+	}
+	_ = tracker.HandleCallback(ctx, &asyncnotify.WithdrawData{OutOrderID: "wd-1", Status: withdraw.StateSuccess})
+
+	_ = tracker.HandleCallback(ctx, &asyncnotify.WithdrawData{OutOrderID: "wd-2", Status: withdraw.StateSuccess, WithdrawAmount: 100})
+	_ = tracker.HandleCallback(ctx, &asyncnotify.WithdrawData{OutOrderID: "wd-2", Status: withdraw.StateReExchange})
+
+	want := []string{withdraw.EventApplied, withdraw.EventSucceeded, withdraw.EventReExchanged, withdraw.EventSucceeded, withdraw.EventReExchanged}
+	if len(events) != len(want) {
+		t.Fatalf("events = %v, want %v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("events = %v, want %v", events, want)
+			break
+		}
+	}
+}
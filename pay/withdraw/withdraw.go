@@ -29,6 +29,18 @@ import (
 	"github.com/houseme/bytedance/utility/base"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type IWithdraw -impl Withdraw
+
+// IWithdraw Withdraw 服务接口，便于替换为 FakeWithdraw 等测试替身
+type IWithdraw interface {
+	// QueryBalance query balance
+	QueryBalance(ctx context.Context, req *QueryBalanceRequest) (*QueryBalanceResponse, error)
+	// Apply to apply withdrawal
+	Apply(ctx context.Context, req *MerchantWithdrawRequest) (*MerchantWithdrawResponse, error)
+	// QueryWithdraw query withdraws
+	QueryWithdraw(ctx context.Context, req *QueryMerchantWithdrawRequest) (*QueryMerchantWithdrawResponse, error)
+}
+
 // Withdraw merchant accounts withdraw
 type Withdraw struct {
 	ctxCfg *credential.ContextConfig
@@ -30,6 +30,14 @@ import (
 	"github.com/houseme/bytedance/utility/helper"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type IAccount -impl Account
+
+// IAccount Account 服务接口，便于替换为 FakeAccount 等测试替身
+type IAccount interface {
+	// QueryBalance query balance
+	QueryBalance(ctx context.Context, req *QueryMerchantAccountRequest) (*QueryMerchantAccountResponse, error)
+}
+
 // Account merchant accounts
 type Account struct {
 	ctxCfg *credential.ContextConfig
@@ -31,6 +31,14 @@ import (
 	"github.com/houseme/bytedance/utility/helper"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type IBill -impl Bill
+
+// IBill Bill 服务接口，便于替换为 FakeBill 等测试替身
+type IBill interface {
+	// QueryBill query bill
+	QueryBill(ctx context.Context, req *QueryBillRequest) (*QueryBillResponse, error)
+}
+
 // Bill merchant accounts bill
 type Bill struct {
 	ctxCfg *credential.ContextConfig
@@ -81,11 +81,44 @@ const (
     AsyncSettleFinish = "settle_finish"
 )
 
+const (
+    // OrderSuccess 支付成功
+    OrderSuccess = "SUCCESS"
+    // OrderTimeout 超时未支付
+    OrderTimeout = "TIMEOUT"
+    // OrderProcessing 支付处理中
+    OrderProcessing = "PROCESSING"
+    // OrderFail 支付失败
+    OrderFail = "FAIL"
+)
+
 const (
     // SettleFail 结算失败
     SettleFail = "FAIL"
     // SettleSuccess 结算成功
     SettleSuccess = "SUCCESS"
+    // SettleProcessing 结算处理中
+    SettleProcessing = "PROCESSING"
+)
+
+const (
+    // RefundSuccess 退款成功
+    RefundSuccess = "SUCCESS"
+    // RefundFail 退款失败
+    RefundFail = "FAIL"
+    // RefundProcessing 退款处理中
+    RefundProcessing = "PROCESSING"
+)
+
+const (
+    // WithdrawSuccess 提现成功
+    WithdrawSuccess = "SUCCESS"
+    // WithdrawFail 提现失败
+    WithdrawFail = "FAIL"
+    // WithdrawProcessing 提现处理中
+    WithdrawProcessing = "PROCESSING"
+    // WithdrawReExchange 退票，银行处理失败后款项退回渠道账户
+    WithdrawReExchange = "REEXCHANGE"
 )
 
 const (
@@ -110,6 +143,9 @@ const (
     
     // FailedToCheckTheSignature 验签失败
     FailedToCheckTheSignature = 400
+    
+    // RequestParameterError 请求参数错误，如未知的回调类型或回调内容解析失败
+    RequestParameterError = 401
 )
 
 // 应用名称
@@ -70,36 +70,36 @@ func (p *Payment) ContextConfig() *credential.ContextConfig {
 }
 
 // Trade payment trade relation
-func (p *Payment) Trade() *trade.Trade {
+func (p *Payment) Trade() trade.ITrade {
 	return trade.NewTrade(p.ContextConfig())
 }
 
 // Withdraw cash
-func (p *Payment) Withdraw() *withdraw.Withdraw {
+func (p *Payment) Withdraw() withdraw.IWithdraw {
 	return withdraw.NewWithdraw(p.ContextConfig())
 }
 
 // Settle account cash
-func (p *Payment) Settle() *settle.Settle {
+func (p *Payment) Settle() settle.ISettle {
 	return settle.NewSettle(p.ContextConfig())
 }
 
 // Refund order cash
-func (p *Payment) Refund() *refund.Refund {
+func (p *Payment) Refund() refund.IRefund {
 	return refund.NewRefund(p.ContextConfig())
 }
 
 // Sync order sync to douyin
-func (p *Payment) Sync() *syncorder.Sync {
+func (p *Payment) Sync() syncorder.ISync {
 	return syncorder.NewSync(p.ContextConfig())
 }
 
 // Account merchant accounts
-func (p *Payment) Account() *account.Account {
+func (p *Payment) Account() account.IAccount {
 	return account.NewAccount(p.ContextConfig())
 }
 
 // Bill merchant bill
-func (p *Payment) Bill() *bill.Bill {
+func (p *Payment) Bill() bill.IBill {
 	return bill.NewBill(p.ContextConfig())
 }
@@ -0,0 +1,70 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package refund
+
+// CreateRefundRequest 发起退款
+// see https://developer.open-douyin.com/docs/resource/zh-CN/mini-app/develop/server/ecpay/refund-list/refund
+type CreateRefundRequest struct {
+    AppID        string `json:"app_id" description:"小程序 APPID"`
+    OutOrderNo   string `json:"out_order_no" description:"商户分配支付单号，标识进行退款的订单"`
+    OutRefundNo  string `json:"out_refund_no" description:"商户分配退款号，保证在商户中唯一"`
+    Reason       string `json:"reason" description:"退款原因，长度限制不超过 100 个字符"`
+    RefundAmount int    `json:"refund_amount" description:"退款金额，单位为分"`
+    CpExtra      string `json:"cp_extra,omitempty" description:"开发者自定义字段，回调原样回传"`
+    NotifyURL    string `json:"notify_url,omitempty" description:"商户自定义回调地址，必须以 HTTPS 开头，支持 443 端口"`
+    ThirdPartyID string `json:"thirdparty_id,omitempty" description:"第三方平台服务商 id，非服务商模式留空"`
+    DisableMsg   int    `json:"disable_msg,omitempty" description:"是否屏蔽退款完成后推送用户抖音消息，1-屏蔽 0-非屏蔽，默认为 0"`
+    MsgPage      string `json:"msg_page,omitempty" description:"退款完成后推送给用户的抖音消息跳转页面"`
+    AllSettle    int    `json:"all_settle,omitempty" description:"是否为分账后退款，1-分账后退款，0-分账前退款，默认为 0"`
+    Sign         string `json:"sign" description:"签名"`
+}
+
+// CreateRefundResponse 发起退款
+type CreateRefundResponse struct {
+    ErrNo    int    `json:"err_no" description:"返回码，0 代表成功 非 0 代表失败"`
+    ErrTips  string `json:"err_tips" description:"返回码信息"`
+    RefundNo string `json:"refund_no" description:"平台生成的退款单号"`
+}
+
+// QueryRefundRequest 查询退款
+type QueryRefundRequest struct {
+    AppID        string `json:"app_id" description:"小程序 APPID"`
+    OutRefundNo  string `json:"out_refund_no" description:"开发者侧的退款号"`
+    ThirdPartyID string `json:"thirdparty_id,omitempty" description:"第三方平台服务商 id，非服务商模式留空"`
+    Sign         string `json:"sign" description:"签名"`
+}
+
+// QueryRefundResponse 查询退款
+type QueryRefundResponse struct {
+    ErrNo      int         `json:"err_no"`
+    ErrTips    string      `json:"err_tips"`
+    RefundInfo *RefundInfo `json:"refundInfo"`
+}
+
+// RefundInfo 退款信息
+type RefundInfo struct {
+    RefundNo     string `json:"refund_no" description:"平台退款单号"`
+    RefundAmount int    `json:"refund_amount" description:"退款金额，单位为分"`
+    RefundStatus string `json:"refund_status" description:"退款状态：PROCESSING：处理中，SUCCESS：成功，FAIL：失败"`
+    RefundedAt   int    `json:"refunded_at" description:"退款时间，Unix 时间戳，10 位"`
+    IsAllSettled bool   `json:"is_all_settled" description:"退款账户，是否为分账后退款"`
+    CpExtra      string `json:"cp_extra" description:"开发者自定义字段"`
+    Msg          string `json:"msg" description:"退款结果信息"`
+}
@@ -20,15 +20,125 @@
 package refund
 
 import (
-    "github.com/houseme/bytedance/credential"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/houseme/bytedance/credential"
+	"github.com/houseme/bytedance/payment/constant"
+	"github.com/houseme/bytedance/payment/trade"
+	"github.com/houseme/bytedance/utility/base"
+	"github.com/houseme/bytedance/utility/helper"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type IRefund -impl Refund
+
+// IRefund Refund 服务接口，便于替换为 FakeRefund 等测试替身
+type IRefund interface {
+	// CreateRefund 发起退款
+	CreateRefund(ctx context.Context, req *CreateRefundRequest) (*CreateRefundResponse, error)
+	// QueryRefund 查询退款
+	QueryRefund(ctx context.Context, req *QueryRefundRequest) (*QueryRefundResponse, error)
+	// Refundable 查询订单剩余可退金额，outRefundNos 为该订单已发起的退款号
+	Refundable(ctx context.Context, outOrderNo string, outRefundNos ...string) (int, error)
+}
+
 // Refund merchant account refund
 type Refund struct {
-    ctxCfg *credential.ContextConfig
+	ctxCfg *credential.ContextConfig
+	trade  trade.ITrade
 }
 
 // NewRefund init
 func NewRefund(cfg *credential.ContextConfig) *Refund {
-    return &Refund{ctxCfg: cfg}
+	return &Refund{ctxCfg: cfg, trade: trade.NewTrade(cfg)}
+}
+
+// CreateRefund 发起退款
+func (r *Refund) CreateRefund(ctx context.Context, req *CreateRefundRequest) (resp *CreateRefundResponse, err error) {
+	r.ctxCfg.Logger().Debug(ctx, "CreateRefund req:", req)
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
+	}
+	if strings.TrimSpace(req.OutOrderNo) == "" || strings.TrimSpace(req.OutRefundNo) == "" {
+		return nil, base.ErrParamKeyValueEmpty("OutOrderNo or OutRefundNo")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, base.ErrParamKeyValueEmpty("Reason")
+	}
+	if req.RefundAmount <= 0 {
+		return nil, fmt.Errorf("%w: refund_amount %d", base.ErrInvalidAmount, req.RefundAmount)
+	}
+	if strings.TrimSpace(req.AppID) == "" {
+		req.AppID = r.ctxCfg.Config.ClientKey()
+	}
+	req.Sign = helper.RequestSign(ctx, *req, r.ctxCfg.Config.Salt())
+
+	var response []byte
+	if response, err = r.ctxCfg.Request().PostJSON(ctx, constant.CreateRefund, *req); err != nil {
+		return nil, err
+	}
+	resp = &CreateRefundResponse{}
+	err = json.Unmarshal(response, resp)
+	return
+}
+
+// QueryRefund 查询退款
+func (r *Refund) QueryRefund(ctx context.Context, req *QueryRefundRequest) (resp *QueryRefundResponse, err error) {
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
+	}
+	if strings.TrimSpace(req.OutRefundNo) == "" {
+		return nil, base.ErrParamKeyValueEmpty("OutRefundNo")
+	}
+	if strings.TrimSpace(req.AppID) == "" {
+		req.AppID = r.ctxCfg.Config.ClientKey()
+	}
+	req.Sign = helper.RequestSign(ctx, *req, r.ctxCfg.Config.Salt())
+
+	var response []byte
+	if response, err = r.ctxCfg.Request().PostJSON(ctx, constant.QueryRefund, *req); err != nil {
+		return nil, err
+	}
+	resp = &QueryRefundResponse{}
+	err = json.Unmarshal(response, resp)
+	return
+}
+
+// Refundable 查询订单剩余可退金额：订单金额减去处理中与成功的退款金额，订单未支付成功时返回 base.ErrOrderNotPaid。
+// 旧版担保支付无法按订单列出退款，需由调用方传入该订单已发起的退款号
+func (r *Refund) Refundable(ctx context.Context, outOrderNo string, outRefundNos ...string) (int, error) {
+	if strings.TrimSpace(outOrderNo) == "" {
+		return 0, base.ErrParamKeyValueEmpty("OutOrderNo")
+	}
+	order, err := r.trade.QueryPay(ctx, &trade.QueryOrderRequest{OutOrderNo: outOrderNo})
+	if err != nil {
+		return 0, err
+	}
+	if order.ErrNo != constant.Success {
+		return 0, base.Error{ErrCode: order.ErrNo, ErrMsg: order.ErrTips}
+	}
+	if order.PaymentInfo == nil || order.PaymentInfo.OrderStatus != constant.OrderSuccess {
+		return 0, fmt.Errorf("%w: %s", base.ErrOrderNotPaid, outOrderNo)
+	}
+
+	remain := order.PaymentInfo.TotalFee
+	for _, no := range outRefundNos {
+		refund, err := r.QueryRefund(ctx, &QueryRefundRequest{OutRefundNo: no})
+		if err != nil {
+			return 0, err
+		}
+		if refund.ErrNo != constant.Success {
+			return 0, base.Error{ErrCode: refund.ErrNo, ErrMsg: refund.ErrTips}
+		}
+		if refund.RefundInfo == nil || refund.RefundInfo.RefundStatus == constant.RefundFail {
+			continue
+		}
+		remain -= refund.RefundInfo.RefundAmount
+	}
+	if remain < 0 {
+		remain = 0
+	}
+	return remain, nil
 }
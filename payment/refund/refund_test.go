@@ -0,0 +1,123 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package refund
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/credential"
+	"github.com/houseme/bytedance/payment/trade"
+	"github.com/houseme/bytedance/utility/base"
+	"github.com/houseme/bytedance/utility/cache"
+)
+
+const (
+	testAppID = "tt-app"
+	testSalt  = "salt"
+)
+
+// ecpayServer 模拟旧版担保支付接口，按路径末段返回 responses 中的报文，received 记录收到的请求体
+func ecpayServer(t *testing.T, responses map[string]string) (cfg *credential.ContextConfig, received map[string]map[string]any) {
+	received = make(map[string]map[string]any)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var m map[string]any
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Errorf("%s body: %v", r.URL.Path, err)
+		}
+		name := path.Base(r.URL.Path)
+		received[name] = m
+		_, _ = io.WriteString(w, responses[name])
+	}))
+	t.Cleanup(srv.Close)
+	ctx := context.Background()
+	cfg = &credential.ContextConfig{Config: config.New(ctx, config.WithClientKey(testAppID), config.WithSalt(testSalt),
+		config.WithBaseURL(srv.URL), config.WithCache(cache.NewMemory()))}
+	return cfg, received
+}
+
+// wantSign 按旧版担保支付规则计算签名：参与签名的值与 salt 排序后以 & 连接取 md5
+func wantSign(values ...string) string {
+	values = append(values, testSalt)
+	sort.Strings(values)
+	return fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(values, "&"))))
+}
+
+func TestRefundSignAndDecode(t *testing.T) {
+	ctx := context.Background()
+	cfg, received := ecpayServer(t, map[string]string{
+		"create_refund": `{"err_no":0,"err_tips":"","refund_no":"rn-1"}`,
+		"query_refund":  `{"err_no":0,"err_tips":"","refundInfo":{"refund_no":"rn-1","refund_amount":300,"refund_status":"SUCCESS","refunded_at":1700000000,"is_all_settled":true}}`,
+	})
+	r := NewRefund(cfg)
+
+	created, err := r.CreateRefund(ctx, &CreateRefundRequest{OutOrderNo: "o1", OutRefundNo: "r1", Reason: "lost", RefundAmount: 300})
+	if err != nil || created.ErrNo != 0 || created.RefundNo != "rn-1" {
+		t.Fatalf("CreateRefund() = %+v, %v", created, err)
+	}
+	body := received["create_refund"]
+	if body["app_id"] != testAppID || body["sign"] != wantSign("o1", "r1", "lost", "300") {
+		t.Errorf("create_refund body = %v", body)
+	}
+
+	queried, err := r.QueryRefund(ctx, &QueryRefundRequest{OutRefundNo: "r1"})
+	if err != nil || queried.RefundInfo == nil || queried.RefundInfo.RefundAmount != 300 || !queried.RefundInfo.IsAllSettled {
+		t.Fatalf("QueryRefund() = %+v, %v", queried, err)
+	}
+	if body = received["query_refund"]; body["app_id"] != testAppID || body["sign"] != wantSign("r1") {
+		t.Errorf("query_refund body = %v", body)
+	}
+
+	if _, err = r.CreateRefund(ctx, &CreateRefundRequest{OutOrderNo: "o1", OutRefundNo: "r2", Reason: "lost"}); !errors.Is(err, base.ErrInvalidAmount) {
+		t.Errorf("CreateRefund() zero amount err = %v, want ErrInvalidAmount", err)
+	}
+}
+
+func TestRefundable(t *testing.T) {
+	ctx := context.Background()
+	cfg, _ := ecpayServer(t, map[string]string{
+		"query_refund": `{"err_no":0,"refundInfo":{"refund_amount":300,"refund_status":"SUCCESS"}}`,
+	})
+	r := NewRefund(cfg)
+	status := "SUCCESS"
+	r.trade = &trade.FakeTrade{QueryPayFunc: func(context.Context, *trade.QueryOrderRequest) (*trade.QueryOrderResponse, error) {
+		return &trade.QueryOrderResponse{PaymentInfo: &trade.PaymentInfo{TotalFee: 1000, OrderStatus: status}}, nil
+	}}
+
+	if remain, err := r.Refundable(ctx, "o1", "r1"); err != nil || remain != 700 {
+		t.Errorf("Refundable() = %d, %v, want 700", remain, err)
+	}
+	status = "PROCESSING"
+	if _, err := r.Refundable(ctx, "o1"); !errors.Is(err, base.ErrOrderNotPaid) {
+		t.Errorf("Refundable() unpaid err = %v, want ErrOrderNotPaid", err)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package settle
+
+// CreateSettleRequest 请求分账
+// see https://developer.open-douyin.com/docs/resource/zh-CN/mini-app/develop/server/ecpay/settlement/settlement
+type CreateSettleRequest struct {
+    AppID        string `json:"app_id" description:"小程序 APPID"`
+    OutSettleNo  string `json:"out_settle_no" description:"开发者侧的分账号，同一小程序下不可重复"`
+    OutOrderNo   string `json:"out_order_no" description:"支付单号，需要分账的商户订单号"`
+    SettleDesc   string `json:"settle_desc" description:"分账描述，长度限制 80 个字符"`
+    SettleParams string `json:"settle_params,omitempty" description:"其他分账方信息，JSON 字符串，分账方为 merchant_uid 与 amount 的数组，可用 EncodeSettleParams 生成"`
+    CpExtra      string `json:"cp_extra,omitempty" description:"开发者自定义字段，回调原样回传"`
+    NotifyURL    string `json:"notify_url,omitempty" description:"商户自定义回调地址，必须以 HTTPS 开头，支持 443 端口"`
+    ThirdPartyID string `json:"thirdparty_id,omitempty" description:"第三方平台服务商 id，非服务商模式留空"`
+    Sign         string `json:"sign" description:"签名"`
+}
+
+// SettleParam 其他分账方
+type SettleParam struct {
+    MerchantUID string `json:"merchant_uid" description:"分账方商户号"`
+    Amount      int    `json:"amount" description:"分账金额，单位为分"`
+}
+
+// CreateSettleResponse 请求分账
+type CreateSettleResponse struct {
+    ErrNo    int    `json:"err_no" description:"返回码，0 代表成功 非 0 代表失败"`
+    ErrTips  string `json:"err_tips" description:"返回码信息"`
+    SettleNo string `json:"settle_no" description:"平台生成的分账单号"`
+}
+
+// QuerySettleRequest 查询分账
+type QuerySettleRequest struct {
+    AppID        string `json:"app_id" description:"小程序 APPID"`
+    OutSettleNo  string `json:"out_settle_no" description:"开发者侧的分账号"`
+    ThirdPartyID string `json:"thirdparty_id,omitempty" description:"第三方平台服务商 id，非服务商模式留空"`
+    Sign         string `json:"sign" description:"签名"`
+}
+
+// QuerySettleResponse 查询分账
+type QuerySettleResponse struct {
+    ErrNo      int         `json:"err_no"`
+    ErrTips    string      `json:"err_tips"`
+    SettleInfo *SettleInfo `json:"settle_info"`
+}
+
+// SettleInfo 分账信息
+type SettleInfo struct {
+    SettleNo     string `json:"settle_no" description:"平台分账单号"`
+    SettleAmount int    `json:"settle_amount" description:"分账金额，单位为分"`
+    SettleStatus string `json:"settle_status" description:"分账状态：PROCESSING：处理中，SUCCESS：成功，FAIL：失败"`
+    CpExtra      string `json:"cp_extra" description:"开发者自定义字段"`
+}
+
+// UnsettleAmountRequest 查询待分账金额
+type UnsettleAmountRequest struct {
+    AppID        string `json:"app_id" description:"小程序 APPID"`
+    ThirdPartyID string `json:"thirdparty_id,omitempty" description:"第三方平台服务商 id，非服务商模式留空"`
+    Sign         string `json:"sign" description:"签名"`
+}
+
+// UnsettleAmountResponse 查询待分账金额
+type UnsettleAmountResponse struct {
+    ErrNo          int    `json:"err_no"`
+    ErrTips        string `json:"err_tips"`
+    UnsettleAmount int    `json:"unsettle_amount" description:"待分账金额，单位为分"`
+}
+
+// QueryPlatformOrderRequest 查询自动结算结果
+type QueryPlatformOrderRequest struct {
+    AppID        string `json:"app_id" description:"小程序 APPID"`
+    OutOrderNo   string `json:"out_order_no" description:"开发者侧的订单号"`
+    ThirdPartyID string `json:"thirdparty_id,omitempty" description:"第三方平台服务商 id，非服务商模式留空"`
+    Sign         string `json:"sign" description:"签名"`
+}
+
+// QueryPlatformOrderResponse 查询自动结算结果
+type QueryPlatformOrderResponse struct {
+    ErrNo      int         `json:"err_no"`
+    ErrTips    string      `json:"err_tips"`
+    SettleInfo *SettleInfo `json:"settle_info" description:"平台自动结算信息，未结算时为空"`
+}
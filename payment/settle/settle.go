@@ -20,15 +20,146 @@
 package settle
 
 import (
-    "github.com/houseme/bytedance/credential"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/houseme/bytedance/credential"
+	"github.com/houseme/bytedance/payment/constant"
+	"github.com/houseme/bytedance/utility/base"
+	"github.com/houseme/bytedance/utility/helper"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type ISettle -impl Settle
+
+// ISettle Settle 服务接口，便于替换为 FakeSettle 等测试替身
+type ISettle interface {
+	// CreateSettle 请求分账
+	CreateSettle(ctx context.Context, req *CreateSettleRequest) (*CreateSettleResponse, error)
+	// QuerySettle 查询分账
+	QuerySettle(ctx context.Context, req *QuerySettleRequest) (*QuerySettleResponse, error)
+	// UnsettleAmount 查询待分账金额
+	UnsettleAmount(ctx context.Context, req *UnsettleAmountRequest) (*UnsettleAmountResponse, error)
+	// QueryPlatformOrder 查询自动结算结果
+	QueryPlatformOrder(ctx context.Context, req *QueryPlatformOrderRequest) (*QueryPlatformOrderResponse, error)
+}
+
 // Settle merchant account settle
 type Settle struct {
-    ctxCfg *credential.ContextConfig
+	ctxCfg *credential.ContextConfig
 }
 
 // NewSettle init
 func NewSettle(cfg *credential.ContextConfig) *Settle {
-    return &Settle{ctxCfg: cfg}
+	return &Settle{ctxCfg: cfg}
+}
+
+// EncodeSettleParams 将其他分账方序列化为 settle_params
+func EncodeSettleParams(params ...*SettleParam) (string, error) {
+	if len(params) == 0 {
+		return "", nil
+	}
+	for _, p := range params {
+		if p == nil || strings.TrimSpace(p.MerchantUID) == "" {
+			return "", base.ErrParamKeyValueEmpty("MerchantUID")
+		}
+		if p.Amount <= 0 {
+			return "", fmt.Errorf("%w: merchant_uid %s amount %d", base.ErrInvalidAmount, p.MerchantUID, p.Amount)
+		}
+	}
+	b, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// CreateSettle 请求分账
+func (s *Settle) CreateSettle(ctx context.Context, req *CreateSettleRequest) (resp *CreateSettleResponse, err error) {
+	s.ctxCfg.Logger().Debug(ctx, "CreateSettle req:", req)
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
+	}
+	if strings.TrimSpace(req.OutSettleNo) == "" || strings.TrimSpace(req.OutOrderNo) == "" {
+		return nil, base.ErrParamKeyValueEmpty("OutSettleNo or OutOrderNo")
+	}
+	if strings.TrimSpace(req.SettleDesc) == "" {
+		return nil, base.ErrParamKeyValueEmpty("SettleDesc")
+	}
+	if strings.TrimSpace(req.AppID) == "" {
+		req.AppID = s.ctxCfg.Config.ClientKey()
+	}
+	req.Sign = helper.RequestSign(ctx, *req, s.ctxCfg.Config.Salt())
+
+	var response []byte
+	if response, err = s.ctxCfg.Request().PostJSON(ctx, constant.CreateSettle, *req); err != nil {
+		return nil, err
+	}
+	resp = &CreateSettleResponse{}
+	err = json.Unmarshal(response, resp)
+	return
+}
+
+// QuerySettle 查询分账
+func (s *Settle) QuerySettle(ctx context.Context, req *QuerySettleRequest) (resp *QuerySettleResponse, err error) {
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
+	}
+	if strings.TrimSpace(req.OutSettleNo) == "" {
+		return nil, base.ErrParamKeyValueEmpty("OutSettleNo")
+	}
+	if strings.TrimSpace(req.AppID) == "" {
+		req.AppID = s.ctxCfg.Config.ClientKey()
+	}
+	req.Sign = helper.RequestSign(ctx, *req, s.ctxCfg.Config.Salt())
+
+	var response []byte
+	if response, err = s.ctxCfg.Request().PostJSON(ctx, constant.QuerySettle, *req); err != nil {
+		return nil, err
+	}
+	resp = &QuerySettleResponse{}
+	err = json.Unmarshal(response, resp)
+	return
+}
+
+// UnsettleAmount 查询待分账金额
+func (s *Settle) UnsettleAmount(ctx context.Context, req *UnsettleAmountRequest) (resp *UnsettleAmountResponse, err error) {
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
+	}
+	if strings.TrimSpace(req.AppID) == "" {
+		req.AppID = s.ctxCfg.Config.ClientKey()
+	}
+	req.Sign = helper.RequestSign(ctx, *req, s.ctxCfg.Config.Salt())
+
+	var response []byte
+	if response, err = s.ctxCfg.Request().PostJSON(ctx, constant.UnsettleAmount, *req); err != nil {
+		return nil, err
+	}
+	resp = &UnsettleAmountResponse{}
+	err = json.Unmarshal(response, resp)
+	return
+}
+
+// QueryPlatformOrder 查询自动结算结果
+func (s *Settle) QueryPlatformOrder(ctx context.Context, req *QueryPlatformOrderRequest) (resp *QueryPlatformOrderResponse, err error) {
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
+	}
+	if strings.TrimSpace(req.OutOrderNo) == "" {
+		return nil, base.ErrParamKeyValueEmpty("OutOrderNo")
+	}
+	if strings.TrimSpace(req.AppID) == "" {
+		req.AppID = s.ctxCfg.Config.ClientKey()
+	}
+	req.Sign = helper.RequestSign(ctx, *req, s.ctxCfg.Config.Salt())
+
+	var response []byte
+	if response, err = s.ctxCfg.Request().PostJSON(ctx, constant.QueryPlatformOrder, *req); err != nil {
+		return nil, err
+	}
+	resp = &QueryPlatformOrderResponse{}
+	err = json.Unmarshal(response, resp)
+	return
 }
@@ -0,0 +1,122 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package settle
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/credential"
+	"github.com/houseme/bytedance/utility/base"
+	"github.com/houseme/bytedance/utility/cache"
+)
+
+const (
+	testAppID = "tt-app"
+	testSalt  = "salt"
+)
+
+// ecpayServer 模拟旧版担保支付接口，按路径末段返回 responses 中的报文，received 记录收到的请求体
+func ecpayServer(t *testing.T, responses map[string]string) (cfg *credential.ContextConfig, received map[string]map[string]any) {
+	received = make(map[string]map[string]any)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var m map[string]any
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Errorf("%s body: %v", r.URL.Path, err)
+		}
+		name := path.Base(r.URL.Path)
+		received[name] = m
+		_, _ = io.WriteString(w, responses[name])
+	}))
+	t.Cleanup(srv.Close)
+	ctx := context.Background()
+	cfg = &credential.ContextConfig{Config: config.New(ctx, config.WithClientKey(testAppID), config.WithSalt(testSalt),
+		config.WithBaseURL(srv.URL), config.WithCache(cache.NewMemory()))}
+	return cfg, received
+}
+
+// wantSign 按旧版担保支付规则计算签名：参与签名的值与 salt 排序后以 & 连接取 md5
+func wantSign(values ...string) string {
+	values = append(values, testSalt)
+	sort.Strings(values)
+	return fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(values, "&"))))
+}
+
+func TestSettleSignAndDecode(t *testing.T) {
+	ctx := context.Background()
+	cfg, received := ecpayServer(t, map[string]string{
+		"settle":               `{"err_no":0,"err_tips":"","settle_no":"sn-1"}`,
+		"query_settle":         `{"err_no":0,"err_tips":"","settle_info":{"settle_no":"sn-1","settle_amount":900,"settle_status":"SUCCESS"}}`,
+		"unsettle_amount":      `{"err_no":0,"err_tips":"","unsettle_amount":1200}`,
+		"query_platform_order": `{"err_no":0,"err_tips":"","settle_info":{"settle_no":"pn-1","settle_amount":500,"settle_status":"PROCESSING"}}`,
+	})
+	s := NewSettle(cfg)
+
+	if _, err := EncodeSettleParams(&SettleParam{MerchantUID: "m1"}); !errors.Is(err, base.ErrInvalidAmount) {
+		t.Errorf("EncodeSettleParams() zero amount err = %v, want ErrInvalidAmount", err)
+	}
+	params, err := EncodeSettleParams(&SettleParam{MerchantUID: "m1", Amount: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := s.CreateSettle(ctx, &CreateSettleRequest{OutSettleNo: "s1", OutOrderNo: "o1", SettleDesc: "desc", SettleParams: params})
+	if err != nil || created.SettleNo != "sn-1" {
+		t.Fatalf("CreateSettle() = %+v, %v", created, err)
+	}
+	if body := received["settle"]; body["app_id"] != testAppID || body["sign"] != wantSign("s1", "o1", "desc", params) {
+		t.Errorf("settle body = %v", body)
+	}
+
+	queried, err := s.QuerySettle(ctx, &QuerySettleRequest{OutSettleNo: "s1"})
+	if err != nil || queried.SettleInfo == nil || queried.SettleInfo.SettleAmount != 900 || queried.SettleInfo.SettleStatus != "SUCCESS" {
+		t.Fatalf("QuerySettle() = %+v, %v", queried, err)
+	}
+	if body := received["query_settle"]; body["app_id"] != testAppID || body["sign"] != wantSign("s1") {
+		t.Errorf("query_settle body = %v", body)
+	}
+
+	unsettled, err := s.UnsettleAmount(ctx, &UnsettleAmountRequest{})
+	if err != nil || unsettled.UnsettleAmount != 1200 {
+		t.Fatalf("UnsettleAmount() = %+v, %v", unsettled, err)
+	}
+	if body := received["unsettle_amount"]; body["app_id"] != testAppID || body["sign"] != wantSign() {
+		t.Errorf("unsettle_amount body = %v", body)
+	}
+
+	platform, err := s.QueryPlatformOrder(ctx, &QueryPlatformOrderRequest{OutOrderNo: "o1"})
+	if err != nil || platform.SettleInfo == nil || platform.SettleInfo.SettleNo != "pn-1" {
+		t.Fatalf("QueryPlatformOrder() = %+v, %v", platform, err)
+	}
+	if body := received["query_platform_order"]; body["app_id"] != testAppID || body["sign"] != wantSign("o1") {
+		t.Errorf("query_platform_order body = %v", body)
+	}
+}
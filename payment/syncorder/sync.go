@@ -29,6 +29,14 @@ import (
 	"github.com/houseme/bytedance/utility/base"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type ISync -impl Sync
+
+// ISync Sync 服务接口，便于替换为 FakeSync 等测试替身
+type ISync interface {
+	// PushOrder PushOrder
+	PushOrder(ctx context.Context, req *OrderSyncRequest) (*OrderSyncResponse, error)
+}
+
 // Sync sync
 type Sync struct {
 	ctxCfg *credential.ContextConfig
@@ -0,0 +1,82 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package trade
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/houseme/bytedance/payment/constant"
+)
+
+var (
+	// ErrTypeMismatch 回调类型与期望的类型不一致
+	ErrTypeMismatch = errors.New("trade: async request type mismatch")
+	// ErrUnknownType 未知的回调类型
+	ErrUnknownType = errors.New("trade: unknown callback type")
+)
+
+// PaymentData 解析支付结果回调，调用前应先通过 AsyncNotify 验签
+func (r *AsyncRequest) PaymentData() (*AsyncPaymentData, error) {
+	data := new(AsyncPaymentData)
+	if err := r.decode(constant.AsyncPay, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// SettleData 解析分账结果回调，调用前应先通过 AsyncNotify 验签
+func (r *AsyncRequest) SettleData() (*AsyncSettleData, error) {
+	data := new(AsyncSettleData)
+	if err := r.decode(constant.AsyncSettle, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// RefundData 解析退款结果回调，调用前应先通过 AsyncNotify 验签
+func (r *AsyncRequest) RefundData() (*AsyncRefundData, error) {
+	data := new(AsyncRefundData)
+	if err := r.decode(constant.AsyncRefund, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// WithdrawData 解析提现结果回调，调用前应先通过 AsyncNotify 验签
+func (r *AsyncRequest) WithdrawData() (*AsyncWithdrawData, error) {
+	data := new(AsyncWithdrawData)
+	if err := r.decode(constant.AsyncWithdraw, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// decode 校验回调类型并将 msg 解析到 v
+func (r *AsyncRequest) decode(typ string, v any) error {
+	if r.Type != typ {
+		return fmt.Errorf("%w: want %s, got %s", ErrTypeMismatch, typ, r.Type)
+	}
+	if err := json.Unmarshal([]byte(r.Msg), v); err != nil {
+		return fmt.Errorf("trade: decode %s msg: %w", typ, err)
+	}
+	return nil
+}
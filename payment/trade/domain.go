@@ -20,10 +20,16 @@
 // Package trade 交易
 package trade
 
-// AsyncResponse async response
+// AsyncResponse async response，序列化后即为返回给平台的应答，解析出的回调内容不参与序列化
 type AsyncResponse struct {
     ErrNo   int    `json:"err_no" description:"返回码，0 代表成功 非 0 代表失败"`
     ErrTips string `json:"err_tips" description:"返回码信息"`
+    
+    Type         string             `json:"-" description:"回调类型"`
+    PaymentData  *AsyncPaymentData  `json:"-" description:"支付结果，type 为 payment 时有值"`
+    SettleData   *AsyncSettleData   `json:"-" description:"分账结果，type 为 settle 时有值"`
+    RefundData   *AsyncRefundData   `json:"-" description:"退款结果，type 为 refund 时有值"`
+    WithdrawData *AsyncWithdrawData `json:"-" description:"提现结果，type 为 withdraw 时有值"`
 }
 
 // AsyncRequest async request
@@ -154,8 +160,8 @@ type QueryOrderResponse struct {
     ErrTips     string       `json:"err_tips"`
     OutOrderNo  string       `json:"out_order_no"`
     OrderId     string       `json:"order_id"`
-    PaymentInfo *PaymentInfo `json:"payment_info"`
-    CpsInfo     string       `json:"cps_info"`
+    PaymentInfo *PaymentInfo `json:"payment_info" description:"支付信息"`
+    CpsInfo     string       `json:"cps_info" description:"CPS 信息，JSON 字符串，可通过 CPS 方法解析，无 CPS 时为空"`
 }
 
 // PaymentInfo payment info
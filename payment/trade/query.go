@@ -0,0 +1,124 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package trade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/houseme/bytedance/payment/constant"
+	"github.com/houseme/bytedance/utility/base"
+)
+
+const (
+	// payTimeLayout pay_time 的格式，为北京时间
+	payTimeLayout = "2006-01-02 15:04:05"
+
+	defaultPollInterval = 2 * time.Second
+)
+
+// ErrPaymentNotCompleted 订单超时未支付或支付失败
+var ErrPaymentNotCompleted = errors.New("trade: payment not completed")
+
+var beijing = time.FixedZone("CST", 8*60*60)
+
+// CPS 解析 cps_info，无 CPS 信息时返回 nil
+func (r *QueryOrderResponse) CPS() (*CpsInfo, error) {
+	if strings.TrimSpace(r.CpsInfo) == "" {
+		return nil, nil
+	}
+	info := new(CpsInfo)
+	if err := json.Unmarshal([]byte(r.CpsInfo), info); err != nil {
+		return nil, fmt.Errorf("trade: decode cps_info: %w", err)
+	}
+	return info, nil
+}
+
+// Paid 是否已支付成功
+func (p *PaymentInfo) Paid() bool {
+	return p != nil && p.OrderStatus == constant.OrderSuccess
+}
+
+// PaidAt 解析支付完成时间，未支付成功时返回零值
+func (p *PaymentInfo) PaidAt() (time.Time, error) {
+	if p == nil || p.PayTime == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(payTimeLayout, p.PayTime, beijing)
+}
+
+// WaitOption WaitForPayment 配置项
+type WaitOption func(*waitOptions)
+
+type waitOptions struct {
+	interval time.Duration
+}
+
+// WithPollInterval 设置轮询间隔，默认 2 秒
+func WithPollInterval(d time.Duration) WaitOption {
+	return func(o *waitOptions) {
+		o.interval = d
+	}
+}
+
+// WaitForPayment 轮询 QueryPay 直到支付成功、订单超时或失败、或 ctx 结束。
+// 支付成功返回查询结果；超时或失败返回查询结果与 ErrPaymentNotCompleted；ctx 结束返回最后一次查询结果与 ctx 的错误
+func WaitForPayment(ctx context.Context, t ITrade, outOrderNo string, opts ...WaitOption) (*QueryOrderResponse, error) {
+	if strings.TrimSpace(outOrderNo) == "" {
+		return nil, base.ErrParamKeyValueEmpty("OutOrderNo")
+	}
+	op := waitOptions{interval: defaultPollInterval}
+	for _, option := range opts {
+		option(&op)
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	var last *QueryOrderResponse
+	for {
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-timer.C:
+		}
+
+		resp, err := t.QueryPay(ctx, &QueryOrderRequest{OutOrderNo: outOrderNo})
+		if err != nil {
+			return last, err
+		}
+		if resp.ErrNo != constant.Success {
+			return resp, base.Error{ErrCode: resp.ErrNo, ErrMsg: resp.ErrTips}
+		}
+		last = resp
+		if resp.PaymentInfo != nil {
+			switch resp.PaymentInfo.OrderStatus {
+			case constant.OrderSuccess:
+				return resp, nil
+			case constant.OrderTimeout, constant.OrderFail:
+				return resp, fmt.Errorf("%w: %s", ErrPaymentNotCompleted, resp.PaymentInfo.OrderStatus)
+			}
+		}
+		timer.Reset(op.interval)
+	}
+}
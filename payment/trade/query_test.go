@@ -0,0 +1,88 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package trade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueryOrderResponseDecode(t *testing.T) {
+	body := `{"err_no":0,"err_tips":"","out_order_no":"o1","order_id":"d1",
+		"payment_info":{"total_fee":1200,"order_status":"SUCCESS","pay_time":"2026-01-10 08:30:00","way":2,"channel_no":"c1","seller_uid":"s1","item_id":"v1","cp_extra":"x"},
+		"cps_info":"{\"share_amount\":\"120\",\"douyin_id\":\"dy1\",\"nickname\":\"nick\"}"}`
+	var resp QueryOrderResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !resp.PaymentInfo.Paid() || resp.PaymentInfo.TotalFee != 1200 || resp.PaymentInfo.Way != 2 {
+		t.Fatalf("payment_info: %+v", resp.PaymentInfo)
+	}
+	paidAt, err := resp.PaymentInfo.PaidAt()
+	if err != nil || !paidAt.Equal(time.Date(2026, 1, 10, 0, 30, 0, 0, time.UTC)) {
+		t.Fatalf("PaidAt() = %v, %v", paidAt, err)
+	}
+	cps, err := resp.CPS()
+	if err != nil || cps.ShareAmount != "120" || cps.DouYinID != "dy1" {
+		t.Fatalf("CPS() = %+v, %v", cps, err)
+	}
+	if cps, err = (&QueryOrderResponse{}).CPS(); cps != nil || err != nil {
+		t.Fatalf("empty CPS() = %+v, %v", cps, err)
+	}
+}
+
+func TestWaitForPayment(t *testing.T) {
+	ctx := context.Background()
+	statuses := map[string][]string{
+		"paid":    {"PROCESSING", "PROCESSING", "SUCCESS"},
+		"expired": {"PROCESSING", "TIMEOUT"},
+		"pending": {"PROCESSING"},
+	}
+	fake := &FakeTrade{
+		QueryPayFunc: func(_ context.Context, req *QueryOrderRequest) (*QueryOrderResponse, error) {
+			list := statuses[req.OutOrderNo]
+			status := list[0]
+			if len(list) > 1 {
+				statuses[req.OutOrderNo] = list[1:]
+			}
+			return &QueryOrderResponse{OutOrderNo: req.OutOrderNo, PaymentInfo: &PaymentInfo{OrderStatus: status}}, nil
+		},
+	}
+
+	resp, err := WaitForPayment(ctx, fake, "paid", WithPollInterval(time.Millisecond))
+	if err != nil || !resp.PaymentInfo.Paid() {
+		t.Fatalf("paid: %+v, %v", resp, err)
+	}
+	if n := fake.Count("QueryPay"); n != 3 {
+		t.Fatalf("QueryPay calls = %d, want 3", n)
+	}
+	if _, err = WaitForPayment(ctx, fake, "expired", WithPollInterval(time.Millisecond)); !errors.Is(err, ErrPaymentNotCompleted) {
+		t.Fatalf("expired: %v", err)
+	}
+	cctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	resp, err = WaitForPayment(cctx, fake, "pending", WithPollInterval(time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) || resp == nil || resp.PaymentInfo.OrderStatus != "PROCESSING" {
+		t.Fatalf("pending: %+v, %v", resp, err)
+	}
+}
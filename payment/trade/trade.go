@@ -22,14 +22,29 @@ package trade
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/houseme/bytedance/credential"
 	"github.com/houseme/bytedance/payment/constant"
+	"github.com/houseme/bytedance/utility/base"
 	"github.com/houseme/bytedance/utility/helper"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type ITrade -impl Trade
+
+// ITrade Trade 服务接口，便于替换为 FakeTrade 等测试替身
+type ITrade interface {
+	// CreatePay 创建支付
+	CreatePay(ctx context.Context, req *CreateOrderRequest) (*CreateOrderResponse, error)
+	// QueryPay 查询支付
+	QueryPay(ctx context.Context, req *QueryOrderRequest) (*QueryOrderResponse, error)
+	// AsyncNotify 异步通知
+	AsyncNotify(ctx context.Context, req *AsyncRequest) (*AsyncResponse, error)
+}
+
 // Trade creates trade relation
 type Trade struct {
 	ctxCfg *credential.ContextConfig
@@ -77,18 +92,45 @@ func (p *Trade) QueryPay(ctx context.Context, req *QueryOrderRequest) (resp *Que
 	return
 }
 
-// AsyncNotify 异步通知
+// AsyncNotify 异步通知，验签通过后按 type 将 msg 解析到对应的回调内容。
+// 验签失败时返回 err_no 400 的 resp，err 为 nil；未知类型与解析失败时返回 error，resp.ErrNo 为对应的应答 err_no
 func (p *Trade) AsyncNotify(ctx context.Context, req *AsyncRequest) (resp *AsyncResponse, err error) {
 	p.ctxCfg.Logger().Debug(ctx, " async notify request params:", req)
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
+	}
 	var sign = helper.CallbackSign(ctx, p.ctxCfg.Config.Token(), *req)
 	resp = &AsyncResponse{
 		ErrNo:   constant.Success,
 		ErrTips: "SUCCESS",
+		Type:    req.Type,
 	}
-	p.ctxCfg.Logger().Debug(ctx, " async notify request sign:", sign, " msg sign: ", req.MsgSignature)
-	if sign != req.MsgSignature {
+	if subtle.ConstantTimeCompare([]byte(sign), []byte(req.MsgSignature)) != 1 {
 		resp.ErrNo = constant.FailedToCheckTheSignature
 		resp.ErrTips = "failed"
+		return resp, nil
+	}
+
+	switch req.Type {
+	case constant.AsyncPay:
+		resp.PaymentData, err = req.PaymentData()
+	case constant.AsyncSettle:
+		resp.SettleData, err = req.SettleData()
+	case constant.AsyncRefund:
+		resp.RefundData, err = req.RefundData()
+	case constant.AsyncWithdraw:
+		resp.WithdrawData, err = req.WithdrawData()
+	case constant.AsyncTransfer, constant.AsyncSettleFinish:
+		// 暂无对应的结构，调用方可自行解析 req.Msg
+	default:
+		resp.ErrNo = constant.RequestParameterError
+		resp.ErrTips = "unknown callback type: " + req.Type
+		return resp, fmt.Errorf("%w: %q", ErrUnknownType, req.Type)
+	}
+	if err != nil {
+		resp.ErrNo = constant.RequestParameterError
+		resp.ErrTips = err.Error()
+		return
 	}
 	return
 }
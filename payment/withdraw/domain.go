@@ -19,10 +19,30 @@
 
 package withdraw
 
+import (
+    "github.com/houseme/bytedance/payment/account"
+)
+
+// QueryBalanceRequest 查询余额，与 account.QueryMerchantAccountRequest 相同
+type QueryBalanceRequest = account.QueryMerchantAccountRequest
+
+// QueryBalanceResponse 查询余额，与 account.QueryMerchantAccountResponse 相同
+type QueryBalanceResponse = account.QueryMerchantAccountResponse
+
+// QueryWithdrawRequest 查询提现
+//
+// Deprecated: 使用 QueryMerchantWithdrawRequest，与 pay/withdraw 保持一致
+type QueryWithdrawRequest = QueryMerchantWithdrawRequest
+
+// QueryWithdrawResponse 查询提现
+//
+// Deprecated: 使用 QueryMerchantWithdrawResponse，与 pay/withdraw 保持一致
+type QueryWithdrawResponse = QueryMerchantWithdrawResponse
+
 // MerchantWithdrawRequest 商户提现
 type MerchantWithdrawRequest struct {
     ThirdPartyID   string `json:"thirdparty_id,omitempty"` // 三方用户唯一标识
-    AppID          string `json:"appId"`
+    AppID          string `json:"app_id,omitempty" description:"小程序的 app_id。在服务商为自己提现的情况下可不填，其他情况必填"`
     MerchantUID    string `json:"merchant_uid" description:"商户号"`
     ChannelType    string `json:"channel_type" description:"渠道类型" desc:"提现渠道枚举值:alipay: 支付宝，wx: 微信，hz: 抖音支付，yeepay: 易宝，yzt: 担保支付企业版聚合账户"`
     WithdrawAmount int    `json:"withdraw_amount" description:"提现金额；单位分"`
@@ -41,21 +61,21 @@ type MerchantWithdrawResponse struct {
     MerchantEntity int    `json:"merchant_entity" description:"抖音信息和光合信号主体标识：1 查抖音信息主体账户余额，2 查光合信号主体账户余额"`
 }
 
-// QueryWithdrawRequest 查询提现
-type QueryWithdrawRequest struct {
+// QueryMerchantWithdrawRequest 查询提现
+type QueryMerchantWithdrawRequest struct {
     ThirdPartyID string `json:"thirdparty_id,omitempty"` // 三方用户唯一标识
-    AppID        string `json:"appId"`
+    AppID        string `json:"app_id,omitempty" description:"小程序的 app_id。在服务商为自己提现的情况下可不填，其他情况必填"`
     MerchantUID  string `json:"merchant_uid" description:"商户号"`
     ChannelType  string `json:"channel_type" description:"渠道类型" desc:"提现渠道枚举值:alipay: 支付宝，wx: 微信，hz: 抖音支付，yeepay: 易宝，yzt: 担保支付企业版聚合账户"`
     OutOrderID   string `json:"out_order_id" description:"外部单号（开发者侧）；唯一标识一笔提现请求"`
     Sign         string `json:"sign" description:"签名"`
 }
 
-// QueryWithdrawResponse 查询提现
+// QueryMerchantWithdrawResponse 查询提现
 // 注：
 // 退票：商户的提现申请请求通过渠道（微信/支付宝/抖音支付）提交给银行处理后，银行返回结果是处理成功，渠道返回给商户提现成功，
 // 但间隔一段时间后，银行再次通知渠道处理失败并返还款项给渠道，渠道再将该笔失败款返还至商户在渠道的账户余额中
-type QueryWithdrawResponse struct {
+type QueryMerchantWithdrawResponse struct {
     ErrNo     int    `json:"err_no"`
     ErrTips   string `json:"err_tips"`
     Status    string `json:"status" description:"状态枚举值：成功:SUCCESS，失败：FAIL，处理中：PROCESSING，退票：REEXCHANGE"`
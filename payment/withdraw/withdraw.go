@@ -20,15 +20,89 @@
 package withdraw
 
 import (
-    "github.com/houseme/bytedance/credential"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/houseme/bytedance/credential"
+	"github.com/houseme/bytedance/payment/account"
+	"github.com/houseme/bytedance/payment/constant"
+	"github.com/houseme/bytedance/utility/base"
+	"github.com/houseme/bytedance/utility/helper"
 )
 
+//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type IWithdraw -impl Withdraw
+
+// IWithdraw Withdraw 服务接口，便于替换为 FakeWithdraw 等测试替身，方法与 pay/withdraw.IWithdraw 一致
+type IWithdraw interface {
+	// QueryBalance query balance
+	QueryBalance(ctx context.Context, req *QueryBalanceRequest) (*QueryBalanceResponse, error)
+	// Apply to apply withdrawal
+	Apply(ctx context.Context, req *MerchantWithdrawRequest) (*MerchantWithdrawResponse, error)
+	// QueryWithdraw query withdraws
+	QueryWithdraw(ctx context.Context, req *QueryMerchantWithdrawRequest) (*QueryMerchantWithdrawResponse, error)
+}
+
 // Withdraw merchant accounts withdraw
 type Withdraw struct {
-    ctxCfg *credential.ContextConfig
+	ctxCfg  *credential.ContextConfig
+	account account.IAccount
 }
 
 // NewWithdraw init
 func NewWithdraw(cfg *credential.ContextConfig) *Withdraw {
-    return &Withdraw{ctxCfg: cfg}
+	return &Withdraw{ctxCfg: cfg, account: account.NewAccount(cfg)}
+}
+
+// QueryBalance query balance，同 account.QueryBalance
+func (w *Withdraw) QueryBalance(ctx context.Context, req *QueryBalanceRequest) (*QueryBalanceResponse, error) {
+	return w.account.QueryBalance(ctx, req)
+}
+
+// Apply to apply withdrawal
+func (w *Withdraw) Apply(ctx context.Context, req *MerchantWithdrawRequest) (resp *MerchantWithdrawResponse, err error) {
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
+	}
+	if strings.TrimSpace(req.MerchantUID) == "" || strings.TrimSpace(req.OutOrderID) == "" {
+		return nil, base.ErrParamKeyValueEmpty("MerchantUID or OutOrderID")
+	}
+	if req.WithdrawAmount <= 0 {
+		return nil, fmt.Errorf("%w: withdraw_amount %d", base.ErrInvalidAmount, req.WithdrawAmount)
+	}
+
+	if strings.TrimSpace(req.ThirdPartyID) == "" && strings.TrimSpace(req.AppID) == "" {
+		req.AppID = w.ctxCfg.Config.ClientKey()
+	}
+	req.Sign = helper.RequestSign(ctx, *req, w.ctxCfg.Config.Salt())
+	var response []byte
+	if response, err = w.ctxCfg.Request().PostJSON(ctx, constant.MerchantWithdraw, *req); err != nil {
+		return nil, err
+	}
+	resp = &MerchantWithdrawResponse{}
+	err = json.Unmarshal(response, resp)
+	return
+}
+
+// QueryWithdraw query withdraws
+func (w *Withdraw) QueryWithdraw(ctx context.Context, req *QueryMerchantWithdrawRequest) (resp *QueryMerchantWithdrawResponse, err error) {
+	if req == nil {
+		return nil, base.ErrRequestIsEmpty
+	}
+	if strings.TrimSpace(req.OutOrderID) == "" {
+		return nil, base.ErrParamKeyValueEmpty("OutOrderID")
+	}
+
+	if strings.TrimSpace(req.ThirdPartyID) == "" && strings.TrimSpace(req.AppID) == "" {
+		req.AppID = w.ctxCfg.Config.ClientKey()
+	}
+	req.Sign = helper.RequestSign(ctx, *req, w.ctxCfg.Config.Salt())
+	var response []byte
+	if response, err = w.ctxCfg.Request().PostJSON(ctx, constant.QueryWithdrawOrder, *req); err != nil {
+		return nil, err
+	}
+	resp = &QueryMerchantWithdrawResponse{}
+	err = json.Unmarshal(response, resp)
+	return
 }
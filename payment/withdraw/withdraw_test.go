@@ -0,0 +1,101 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package withdraw
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/credential"
+	"github.com/houseme/bytedance/utility/base"
+	"github.com/houseme/bytedance/utility/cache"
+)
+
+const (
+	testAppID = "tt-app"
+	testSalt  = "salt"
+)
+
+// ecpayServer 模拟旧版担保支付接口，按路径末段返回 responses 中的报文，received 记录收到的请求体
+func ecpayServer(t *testing.T, responses map[string]string) (cfg *credential.ContextConfig, received map[string]map[string]any) {
+	received = make(map[string]map[string]any)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var m map[string]any
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Errorf("%s body: %v", r.URL.Path, err)
+		}
+		name := path.Base(r.URL.Path)
+		received[name] = m
+		_, _ = io.WriteString(w, responses[name])
+	}))
+	t.Cleanup(srv.Close)
+	ctx := context.Background()
+	cfg = &credential.ContextConfig{Config: config.New(ctx, config.WithClientKey(testAppID), config.WithSalt(testSalt),
+		config.WithBaseURL(srv.URL), config.WithCache(cache.NewMemory()))}
+	return cfg, received
+}
+
+// wantSign 按旧版担保支付规则计算签名：参与签名的值与 salt 排序后以 & 连接取 md5
+func wantSign(values ...string) string {
+	values = append(values, testSalt)
+	sort.Strings(values)
+	return fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(values, "&"))))
+}
+
+func TestWithdrawSignAndDecode(t *testing.T) {
+	ctx := context.Background()
+	cfg, received := ecpayServer(t, map[string]string{
+		"merchant_withdraw":    `{"err_no":0,"err_tips":"","order_id":"wd-1","merchant_entity":2}`,
+		"query_withdraw_order": `{"err_no":0,"err_tips":"","status":"SUCCESS","statusMsg":"ok"}`,
+	})
+	w := NewWithdraw(cfg)
+
+	if _, err := w.Apply(ctx, &MerchantWithdrawRequest{MerchantUID: "m1", OutOrderID: "w0"}); !errors.Is(err, base.ErrInvalidAmount) {
+		t.Errorf("Apply() zero amount err = %v, want ErrInvalidAmount", err)
+	}
+	applied, err := w.Apply(ctx, &MerchantWithdrawRequest{MerchantUID: "m1", ChannelType: "hz", WithdrawAmount: 500, OutOrderID: "w1", MerchantEntity: 2})
+	if err != nil || applied.OrderID != "wd-1" || applied.MerchantEntity != 2 {
+		t.Fatalf("Apply() = %+v, %v", applied, err)
+	}
+	if body := received["merchant_withdraw"]; body["app_id"] != testAppID || body["sign"] != wantSign("m1", "hz", "500", "w1", "2") {
+		t.Errorf("merchant_withdraw body = %v", body)
+	}
+
+	queried, err := w.QueryWithdraw(ctx, &QueryMerchantWithdrawRequest{ThirdPartyID: "tp-1", MerchantUID: "m1", ChannelType: "hz", OutOrderID: "w1"})
+	if err != nil || queried.Status != "SUCCESS" || queried.StatusMsg != "ok" {
+		t.Fatalf("QueryWithdraw() = %+v, %v", queried, err)
+	}
+	body := received["query_withdraw_order"]
+	if _, ok := body["app_id"]; ok || body["thirdparty_id"] != "tp-1" || body["sign"] != wantSign("m1", "hz", "w1") {
+		t.Errorf("query_withdraw_order body = %v", body)
+	}
+}
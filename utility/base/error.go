@@ -63,6 +63,54 @@ var (
         ErrCode: 10404,
         ErrMsg:  "client access token is empty",
     }
+    
+    // ErrCallbackTimestampExpired callback timestamp is outside the allowed skew window
+    ErrCallbackTimestampExpired = Error{
+        ErrCode: 10408,
+        ErrMsg:  "callback timestamp expired",
+    }
+    
+    // ErrCallbackNonceReused callback nonce has been used before
+    ErrCallbackNonceReused = Error{
+        ErrCode: 10409,
+        ErrMsg:  "callback nonce reused",
+    }
+    
+    // ErrRefundAmountExceeded refunded total would exceed the paid amount
+    ErrRefundAmountExceeded = Error{
+        ErrCode: 10410,
+        ErrMsg:  "refund amount exceeds paid amount",
+    }
+    
+    // ErrOrderNotFound order query returned no data
+    ErrOrderNotFound = Error{
+        ErrCode: 10411,
+        ErrMsg:  "order not found",
+    }
+    
+    // ErrItemOrderNotFound item_order_id does not belong to the order
+    ErrItemOrderNotFound = Error{
+        ErrCode: 10412,
+        ErrMsg:  "item order not found",
+    }
+    
+    // ErrRefundAmountMismatch refund_total_amount differs from the item_order_detail total
+    ErrRefundAmountMismatch = Error{
+        ErrCode: 10413,
+        ErrMsg:  "refund total amount mismatch",
+    }
+    
+    // ErrInvalidAmount amount must be positive
+    ErrInvalidAmount = Error{
+        ErrCode: 10414,
+        ErrMsg:  "amount must be positive",
+    }
+    
+    // ErrOrderNotPaid order has not been paid successfully
+    ErrOrderNotPaid = Error{
+        ErrCode: 10415,
+        ErrMsg:  "order is not paid",
+    }
 )
 
 // ErrConfigKeyValueEmpty params key not found
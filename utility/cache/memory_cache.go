@@ -0,0 +1,94 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryItem 缓存项
+type memoryItem struct {
+	val      interface{}
+	expireAt time.Time
+}
+
+// Memory 进程内缓存，适用于单实例部署与测试
+type Memory struct {
+	mu    sync.Mutex
+	items map[string]memoryItem
+}
+
+// NewMemory 实例化
+func NewMemory() *Memory {
+	return &Memory{items: make(map[string]memoryItem)}
+}
+
+// load 读取未过期的缓存项，调用方需持有锁
+func (m *Memory) load(key string) (memoryItem, bool) {
+	item, ok := m.items[key]
+	if !ok {
+		return item, false
+	}
+	if !item.expireAt.IsZero() && !time.Now().Before(item.expireAt) {
+		delete(m.items, key)
+		return item, false
+	}
+	return item, true
+}
+
+// Get 获取一个值，不存在时与 Redis 一致返回空字符串
+func (m *Memory) Get(_ context.Context, key string) interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if item, ok := m.load(key); ok {
+		return item.val
+	}
+	return ""
+}
+
+// Set 设置一个值，timeout 小于等于 0 时不过期
+func (m *Memory) Set(_ context.Context, key string, val interface{}, timeout time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item := memoryItem{val: val}
+	if timeout > 0 {
+		item.expireAt = time.Now().Add(timeout)
+	}
+	m.items[key] = item
+	return nil
+}
+
+// IsExist 判断 key 是否存在
+func (m *Memory) IsExist(_ context.Context, key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.load(key)
+	return ok
+}
+
+// Delete 删除
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, key)
+	return nil
+}
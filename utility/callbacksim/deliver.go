@@ -0,0 +1,133 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package callbacksim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/houseme/bytedance/minidrama/drama"
+	"github.com/houseme/bytedance/pay/asyncnotify"
+	"github.com/houseme/bytedance/payment/trade"
+)
+
+const (
+	headerContentType = "Content-Type"
+	contentTypeJSON   = "application/json"
+)
+
+// Ack 回调处理方的应答
+type Ack struct {
+	StatusCode int
+	Body       []byte
+	ErrNo      int
+	ErrTips    string
+}
+
+// Success 应答是否表示处理成功
+func (a *Ack) Success() bool {
+	return a.StatusCode == http.StatusOK && a.ErrNo == 0
+}
+
+// ParseAck 校验应答报文格式，应答必须是包含整型 err_no 与字符串 err_tips 的 JSON 对象
+func ParseAck(statusCode int, body []byte) (*Ack, error) {
+	ack := &Ack{StatusCode: statusCode, Body: body}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return ack, fmt.Errorf("callbacksim: response body is not a json object: %w", err)
+	}
+	errNo, ok := fields["err_no"]
+	if !ok {
+		return ack, fmt.Errorf("callbacksim: response body missing err_no: %s", body)
+	}
+	if err := json.Unmarshal(errNo, &ack.ErrNo); err != nil {
+		return ack, fmt.Errorf("callbacksim: err_no must be an integer: %s", errNo)
+	}
+	errTips, ok := fields["err_tips"]
+	if !ok {
+		return ack, fmt.Errorf("callbacksim: response body missing err_tips: %s", body)
+	}
+	if err := json.Unmarshal(errTips, &ack.ErrTips); err != nil {
+		return ack, fmt.Errorf("callbacksim: err_tips must be a string: %s", errTips)
+	}
+	return ack, nil
+}
+
+// DeliverPay 将担保支付回调投递到 url，报文主体为 Content，签名信息放在 Byte-* 请求头中
+func (s *Simulator) DeliverPay(ctx context.Context, url string, req *asyncnotify.AsyncRequest) (*Ack, error) {
+	return s.post(ctx, url, []byte(req.Content), map[string]string{
+		asyncnotify.HeaderIdentifyName: req.ByteIdentifyName,
+		asyncnotify.HeaderLogID:        req.ByteLogID,
+		asyncnotify.HeaderNonceStr:     req.ByteNonceStr,
+		asyncnotify.HeaderSignature:    req.ByteSignature,
+		asyncnotify.HeaderTimestamp:    req.ByteTimestamp,
+	})
+}
+
+// DeliverDrama 将短剧回调投递到 url，报文主体为 Content，签名信息放在 Byte-* 请求头中
+func (s *Simulator) DeliverDrama(ctx context.Context, url string, req *drama.AsyncRequest) (*Ack, error) {
+	return s.post(ctx, url, []byte(req.Content), map[string]string{
+		asyncnotify.HeaderNonceStr:  req.ByteNonceStr,
+		asyncnotify.HeaderSignature: req.ByteSignature,
+		asyncnotify.HeaderTimestamp: req.ByteTimestamp,
+	})
+}
+
+// DeliverEcpay 将旧版担保支付回调以 JSON 报文投递到 url
+func (s *Simulator) DeliverEcpay(ctx context.Context, url string, req *trade.AsyncRequest) (*Ack, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.post(ctx, url, body, nil)
+}
+
+// post 投递回调并校验应答格式
+func (s *Simulator) post(ctx context.Context, url string, body []byte, headers map[string]string) (*Ack, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(headerContentType, contentTypeJSON)
+	for k, v := range headers {
+		if strings.TrimSpace(v) != "" {
+			req.Header.Set(k, v)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAck(resp.StatusCode, respBody)
+}
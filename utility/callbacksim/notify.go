@@ -0,0 +1,163 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package callbacksim
+
+import (
+	"github.com/houseme/bytedance/minidrama/drama"
+	"github.com/houseme/bytedance/pay/asyncnotify"
+	paytrade "github.com/houseme/bytedance/pay/trade"
+	"github.com/houseme/bytedance/payment/constant"
+	"github.com/houseme/bytedance/payment/trade"
+)
+
+// payVersion 担保支付回调版本号
+const payVersion = "3.0"
+
+// PayNotify 生成担保支付回调，typ 为 asyncnotify.AsyncPay 等回调类型，msg 为回调内容结构体或 JSON 字符串
+func (s *Simulator) PayNotify(typ string, msg any) (*asyncnotify.AsyncRequest, error) {
+	env, err := s.envelope(typ, payVersion, msg)
+	if err != nil {
+		return nil, err
+	}
+	return &asyncnotify.AsyncRequest{
+		Content:          env.content,
+		Version:          payVersion,
+		Msg:              env.msg,
+		Type:             typ,
+		ByteIdentifyName: s.identifyName,
+		ByteLogID:        logID(env.nonce),
+		ByteNonceStr:     env.nonce,
+		ByteSignature:    env.signature,
+		ByteTimestamp:    env.timestamp,
+	}, nil
+}
+
+// Payment 生成担保支付支付结果回调
+func (s *Simulator) Payment(data *asyncnotify.PaymentData) (*asyncnotify.AsyncRequest, error) {
+	return s.PayNotify(asyncnotify.AsyncPay, data)
+}
+
+// Settle 生成担保支付分账结果回调
+func (s *Simulator) Settle(data *asyncnotify.SettleData) (*asyncnotify.AsyncRequest, error) {
+	return s.PayNotify(asyncnotify.AsyncSettle, data)
+}
+
+// Refund 生成担保支付退款结果回调
+func (s *Simulator) Refund(data *asyncnotify.RefundData) (*asyncnotify.AsyncRequest, error) {
+	return s.PayNotify(asyncnotify.AsyncRefund, data)
+}
+
+// RefundAudit 生成担保支付退款申请（审核）回调
+func (s *Simulator) RefundAudit(data *asyncnotify.RefundAuditData) (*asyncnotify.AsyncRequest, error) {
+	return s.PayNotify(asyncnotify.AsyncRefundAudit, data)
+}
+
+// PreCreateOrder 生成交易系统预下单回调
+func (s *Simulator) PreCreateOrder(data *paytrade.PreCreateOrderData) (*asyncnotify.AsyncRequest, error) {
+	return s.PayNotify(paytrade.AsyncPreCreateOrder, data)
+}
+
+// Withdraw 生成担保支付提现结果回调
+func (s *Simulator) Withdraw(data *asyncnotify.WithdrawData) (*asyncnotify.AsyncRequest, error) {
+	return s.PayNotify(asyncnotify.AsyncWithdraw, data)
+}
+
+// Transfer 生成担保支付转账结果回调
+func (s *Simulator) Transfer(data *asyncnotify.TransferData) (*asyncnotify.AsyncRequest, error) {
+	return s.PayNotify(asyncnotify.AsyncTransfer, data)
+}
+
+// SettleFinish 生成担保支付自动分账完成回调
+func (s *Simulator) SettleFinish(data *asyncnotify.SettleFinishData) (*asyncnotify.AsyncRequest, error) {
+	return s.PayNotify(asyncnotify.AsyncSettleFinish, data)
+}
+
+// DramaNotify 生成短剧回调，typ 为 drama.AlbumAudit 等回调类型，msg 为回调内容结构体或 JSON 字符串
+func (s *Simulator) DramaNotify(typ string, msg any) (*drama.AsyncRequest, error) {
+	env, err := s.envelope(typ, drama.DefaultAsyncVersion, msg)
+	if err != nil {
+		return nil, err
+	}
+	return &drama.AsyncRequest{
+		Content:       env.content,
+		Msg:           env.msg,
+		Type:          typ,
+		Version:       drama.DefaultAsyncVersion,
+		ByteTimestamp: env.timestamp,
+		ByteNonceStr:  env.nonce,
+		ByteSignature: env.signature,
+	}, nil
+}
+
+// AlbumAudit 生成短剧审核结果回调
+func (s *Simulator) AlbumAudit(data *drama.AsyncAlbumAudit) (*drama.AsyncRequest, error) {
+	return s.DramaNotify(drama.AlbumAudit, data)
+}
+
+// EpisodeAudit 生成剧集审核结果回调
+func (s *Simulator) EpisodeAudit(data *drama.AsyncEpisodeAudit) (*drama.AsyncRequest, error) {
+	return s.DramaNotify(drama.EpisodeAudit, data)
+}
+
+// UploadVideo 生成视频上传结果回调
+func (s *Simulator) UploadVideo(data *drama.AsyncUploadVideo) (*drama.AsyncRequest, error) {
+	return s.DramaNotify(drama.UploadVideo, data)
+}
+
+// EcpayNotify 生成旧版担保支付回调，typ 为 constant.AsyncPay 等回调类型，使用 token 计算 msg_signature
+func (s *Simulator) EcpayNotify(typ string, msg any) (*trade.AsyncRequest, error) {
+	msgStr, err := marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	req := &trade.AsyncRequest{
+		Timestamp: s.timestamp(),
+		Nonce:     s.nonce(),
+		Msg:       msgStr,
+		Type:      typ,
+	}
+	req.MsgSignature = s.tokenSign(*req)
+	return req, nil
+}
+
+// EcpayPayment 生成旧版担保支付支付结果回调
+func (s *Simulator) EcpayPayment(data *trade.AsyncPaymentData) (*trade.AsyncRequest, error) {
+	return s.EcpayNotify(constant.AsyncPay, data)
+}
+
+// EcpaySettle 生成旧版担保支付分账结果回调
+func (s *Simulator) EcpaySettle(data *trade.AsyncSettleData) (*trade.AsyncRequest, error) {
+	return s.EcpayNotify(constant.AsyncSettle, data)
+}
+
+// EcpayRefund 生成旧版担保支付退款结果回调
+func (s *Simulator) EcpayRefund(data *trade.AsyncRefundData) (*trade.AsyncRequest, error) {
+	return s.EcpayNotify(constant.AsyncRefund, data)
+}
+
+// EcpayWithdraw 生成旧版担保支付提现结果回调
+func (s *Simulator) EcpayWithdraw(data *trade.AsyncWithdrawData) (*trade.AsyncRequest, error) {
+	return s.EcpayNotify(constant.AsyncWithdraw, data)
+}
+
+// logID 根据 nonce 生成 Byte-LogId
+func logID(nonce string) string {
+	return "callbacksim-" + nonce
+}
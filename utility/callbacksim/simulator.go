@@ -0,0 +1,238 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+// Package callbacksim 模拟抖音开放平台的回调通知，生成带有合法签名的回调请求，用于测试回调处理逻辑
+//
+// 担保支付 (pay/asyncnotify) 与短剧 (minidrama/drama) 回调使用 RSA 私钥签名，
+// 配置 config.WithPublicKey(sim.PublicKey()) 后即可通过验签；
+// 旧版担保支付 (payment/trade) 回调使用 token 签名，配置 config.WithToken(sim.Token())。
+package callbacksim
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/utility/helper"
+)
+
+const (
+	// defaultToken 默认的回调 token
+	defaultToken = "callbacksim-token"
+	// defaultIdentifyName 默认的回调标识
+	defaultIdentifyName = "douyin-callback"
+	// keyBits 生成测试密钥的长度
+	keyBits = 2048
+)
+
+// Simulator 回调模拟器
+type Simulator struct {
+	privateKey    *rsa.PrivateKey
+	privateKeyPEM string
+	publicKeyPEM  string
+	token         string
+	identifyName  string
+	now           func() time.Time
+	nonce         func() string
+}
+
+type options struct {
+	privateKey   string
+	keyType      config.Secret
+	token        string
+	identifyName string
+	now          func() time.Time
+	nonce        func() string
+}
+
+// Option simulator option
+type Option func(*options)
+
+// WithPrivateKey 使用指定的 PEM 私钥签名，不设置时自动生成测试密钥
+func WithPrivateKey(privateKey string, keyType config.Secret) Option {
+	return func(o *options) {
+		o.privateKey = privateKey
+		o.keyType = keyType
+	}
+}
+
+// WithToken 设置旧版担保支付回调签名使用的 token
+func WithToken(token string) Option {
+	return func(o *options) {
+		o.token = token
+	}
+}
+
+// WithIdentifyName 设置 Byte-Identifyname 回调标识
+func WithIdentifyName(identifyName string) Option {
+	return func(o *options) {
+		o.identifyName = identifyName
+	}
+}
+
+// WithClock 设置时间来源，用于生成过期或固定时间戳的回调
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// WithNonce 设置随机串生成函数，用于生成重复 nonce 的回调
+func WithNonce(nonce func() string) Option {
+	return func(o *options) {
+		o.nonce = nonce
+	}
+}
+
+// New 创建回调模拟器
+func New(opts ...Option) (*Simulator, error) {
+	op := options{
+		token:        defaultToken,
+		identifyName: defaultIdentifyName,
+		now:          time.Now,
+		nonce:        func() string { return helper.RandomStr(32) },
+	}
+	for _, option := range opts {
+		option(&op)
+	}
+
+	var (
+		privateKey *rsa.PrivateKey
+		err        error
+	)
+	if op.privateKey == "" {
+		if privateKey, err = rsa.GenerateKey(rand.Reader, keyBits); err != nil {
+			return nil, err
+		}
+	} else {
+		block, _ := pem.Decode([]byte(op.privateKey))
+		if block == nil {
+			return nil, errors.New("callbacksim: invalid private key pem")
+		}
+		if privateKey, err = helper.ParsePrivateKey(block.Bytes, op.keyType); err != nil {
+			return nil, err
+		}
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Simulator{
+		privateKey:    privateKey,
+		privateKeyPEM: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		publicKeyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+		token:         op.token,
+		identifyName:  op.identifyName,
+		now:           op.now,
+		nonce:         op.nonce,
+	}, nil
+}
+
+// PublicKey 返回验签使用的 PEM 公钥，用于 config.WithPublicKey
+func (s *Simulator) PublicKey() string {
+	return s.publicKeyPEM
+}
+
+// PrivateKey 返回签名使用的 PKCS8 PEM 私钥
+func (s *Simulator) PrivateKey() string {
+	return s.privateKeyPEM
+}
+
+// Token 返回旧版担保支付回调签名使用的 token，用于 config.WithToken
+func (s *Simulator) Token() string {
+	return s.token
+}
+
+// timestamp 当前秒级时间戳
+func (s *Simulator) timestamp() string {
+	return strconv.FormatInt(s.now().Unix(), 10)
+}
+
+// sign 按照 timestamp\nnonce\nbody\n 的格式使用 RSA SHA256 签名，与 helper.CheckSign 对应
+func (s *Simulator) sign(timestamp, nonce, body string) (string, error) {
+	hashed := sha256.Sum256([]byte(timestamp + "\n" + nonce + "\n" + body + "\n"))
+	signBytes, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signBytes), nil
+}
+
+// signedEnvelope 生成签名回调的公共部分
+type signedEnvelope struct {
+	content   string
+	msg       string
+	timestamp string
+	nonce     string
+	signature string
+}
+
+// envelope 将 msg 序列化并与 type、version 组装为回调报文后签名
+func (s *Simulator) envelope(typ, version string, msg any) (*signedEnvelope, error) {
+	msgBytes, err := marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	content, err := marshal(map[string]string{
+		"version": version,
+		"msg":     msgBytes,
+		"type":    typ,
+	})
+	if err != nil {
+		return nil, err
+	}
+	env := &signedEnvelope{content: content, msg: msgBytes, timestamp: s.timestamp(), nonce: s.nonce()}
+	if env.signature, err = s.sign(env.timestamp, env.nonce, env.content); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// marshal 序列化为 JSON 字符串，string 类型的 msg 原样返回
+func marshal(v any) (string, error) {
+	if str, ok := v.(string); ok {
+		return str, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// tokenSign 旧版担保支付回调签名
+func (s *Simulator) tokenSign(data any) string {
+	return helper.CallbackSign(context.Background(), s.token, data)
+}
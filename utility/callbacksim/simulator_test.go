@@ -0,0 +1,145 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package callbacksim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/credential"
+	"github.com/houseme/bytedance/minidrama/drama"
+	"github.com/houseme/bytedance/pay/asyncnotify"
+	"github.com/houseme/bytedance/payment/constant"
+	"github.com/houseme/bytedance/payment/trade"
+	"github.com/houseme/bytedance/utility/cache"
+)
+
+func TestSimulatorSignatures(t *testing.T) {
+	sim, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	ctxCfg := &credential.ContextConfig{
+		Config: config.New(ctx, config.WithPublicKey(sim.PublicKey()), config.WithToken(sim.Token())),
+	}
+
+	payReq, err := sim.Settle(&asyncnotify.SettleData{SettleID: "stl-1", Status: "SUCCESS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payResp, err := asyncnotify.NewAsyncNotify(ctxCfg).AsyncNotify(ctx, payReq)
+	if err != nil || payResp.ErrNo != asyncnotify.ErrNoSuccess || payResp.SettleData.SettleID != "stl-1" {
+		t.Fatalf("pay AsyncNotify() = %+v, %v", payResp, err)
+	}
+
+	// 重放与验签失败一致：返回 err_no 400 的应答，err 为 nil
+	guarded := &credential.ContextConfig{Config: config.New(ctx,
+		config.WithPublicKey(sim.PublicKey()),
+		config.WithCache(cache.NewMemory()),
+		config.WithNonceTTL(time.Hour),
+	)}
+	for i, want := range []int{asyncnotify.ErrNoSuccess, asyncnotify.ErrNoFailedToCheckTheSignature} {
+		if resp, err := asyncnotify.NewAsyncNotify(guarded).AsyncNotify(ctx, payReq); err != nil || resp.ErrNo != want {
+			t.Fatalf("pay AsyncNotify() delivery %d = %+v, %v, want err_no %d", i+1, resp, err, want)
+		}
+	}
+
+	withdrawReq, _ := sim.Withdraw(&asyncnotify.WithdrawData{OrderID: "wd-1", WithdrawAmount: 300})
+	withdrawResp, err := asyncnotify.NewAsyncNotify(ctxCfg).AsyncNotify(ctx, withdrawReq)
+	if err != nil || withdrawResp.WithdrawData == nil || withdrawResp.WithdrawData.WithdrawAmount != 300 {
+		t.Fatalf("withdraw AsyncNotify() = %+v, %v", withdrawResp, err)
+	}
+	unknownReq, _ := sim.PayNotify("coupon", `{"order_id":"ord-1"}`)
+	unknownResp, err := asyncnotify.NewAsyncNotify(ctxCfg).AsyncNotify(ctx, unknownReq)
+	if !errors.Is(err, asyncnotify.ErrUnknownType) || unknownResp.ErrNo != asyncnotify.ErrNoRequestParameterError {
+		t.Fatalf("unknown type AsyncNotify() = %+v, %v, want ErrUnknownType", unknownResp, err)
+	}
+
+	dramaReq, err := sim.AlbumAudit(&drama.AsyncAlbumAudit{AlbumID: 1, AuditStatus: drama.AuditStatusPass})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dramaResp, err := drama.NewDrama(ctxCfg).AsyncNotify(ctx, dramaReq)
+	if err != nil || dramaResp.ErrNo != drama.ErrNoSuccess || dramaResp.AlbumAudit.AlbumID != 1 {
+		t.Fatalf("drama AsyncNotify() = %+v, %v", dramaResp, err)
+	}
+
+	ecpayReq, err := sim.EcpayRefund(&trade.AsyncRefundData{CpRefundNo: "rf-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecpayResp, err := trade.NewTrade(ctxCfg).AsyncNotify(ctx, ecpayReq)
+	if err != nil || ecpayResp.ErrNo != 0 || ecpayResp.RefundData == nil || ecpayResp.RefundData.CpRefundNo != "rf-1" {
+		t.Fatalf("ecpay AsyncNotify() = %+v, %v", ecpayResp, err)
+	}
+	ecpayReq.MsgSignature = strings.Repeat("0", len(ecpayReq.MsgSignature))
+	ecpayResp, err = trade.NewTrade(ctxCfg).AsyncNotify(ctx, ecpayReq)
+	if err != nil || ecpayResp.ErrNo != constant.FailedToCheckTheSignature || ecpayResp.RefundData != nil {
+		t.Errorf("forged ecpay AsyncNotify() = %+v, %v, want err_no %d", ecpayResp, err, constant.FailedToCheckTheSignature)
+	}
+
+	payReq.Msg = `{"settle_id":"forged"}`
+	payReq.Content = `{"msg":"{\"settle_id\":\"forged\"}","type":"settle","version":"3.0"}`
+	if payResp, _ = asyncnotify.NewAsyncNotify(ctxCfg).AsyncNotify(ctx, payReq); payResp.ErrNo != asyncnotify.ErrNoFailedToCheckTheSignature {
+		t.Errorf("tampered pay AsyncNotify() err_no = %d, want %d", payResp.ErrNo, asyncnotify.ErrNoFailedToCheckTheSignature)
+	}
+}
+
+func TestSimulatorDeliver(t *testing.T) {
+	sim, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotSignature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSignature = r.Header.Get("Byte-Signature")
+		_, _ = io.ReadAll(r.Body)
+		if r.URL.Path == "/broken" {
+			_, _ = w.Write([]byte(`{"err_no":"0"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"err_no": 0, "err_tips": "success"})
+	}))
+	defer server.Close()
+
+	req, err := sim.Payment(&asyncnotify.PaymentData{OrderID: "ord-1", Status: asyncnotify.StateSuccess})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ack, err := sim.DeliverPay(context.Background(), server.URL+"/notify", req)
+	if err != nil || !ack.Success() {
+		t.Fatalf("DeliverPay() = %+v, %v", ack, err)
+	}
+	if gotSignature != req.ByteSignature {
+		t.Errorf("Byte-Signature header = %q, want %q", gotSignature, req.ByteSignature)
+	}
+	if _, err = sim.DeliverPay(context.Background(), server.URL+"/broken", req); err == nil {
+		t.Error("DeliverPay() to malformed handler should fail")
+	}
+}
@@ -0,0 +1,474 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+// Command fakegen 按服务接口生成 fake.go 测试替身，替身嵌入 fake.Recorder 并为每个方法提供 XxxFunc 字段。
+// 在接口所在文件中声明：
+//
+//	//go:generate go run github.com/houseme/bytedance/utility/fake/cmd/fakegen -type IRefund -impl Refund
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	// modulePath 本模块路径，用于定位接口签名引用的包
+	modulePath = "github.com/houseme/bytedance"
+	// fakeImport Recorder 所在的包
+	fakeImport = modulePath + "/utility/fake"
+	// outputFile 生成的文件名
+	outputFile = "fake.go"
+)
+
+func main() {
+	var (
+		typ  = flag.String("type", "", "接口名，如 IRefund")
+		impl = flag.String("impl", "", "实现该接口的服务类型，如 Refund")
+	)
+	flag.Parse()
+	if *typ == "" || *impl == "" {
+		log.Fatal("fakegen: -type and -impl are required")
+	}
+	src, err := generate(".", *typ, *impl)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = os.WriteFile(outputFile, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// method 接口方法
+type method struct {
+	Name    string
+	Params  string // 形参列表
+	Results string // 返回值列表
+	Args    string // 调用 XxxFunc 的实参
+	Record  string // 记录的实参，不含 ctx
+	Zero    string // 未设置 XxxFunc 时的返回值
+}
+
+// fakeData 模板数据
+type fakeData struct {
+	Package string
+	Std     []string
+	Others  []string
+	Iface   string
+	Impl    string
+	Fake    string
+	Methods []method
+}
+
+// generate 解析 dir 中的接口 typ 并生成替身源码
+func generate(dir, typ, impl string) ([]byte, error) {
+	pkg, err := parsePackage(dir)
+	if err != nil {
+		return nil, err
+	}
+	file, iface := pkg.lookupInterface(typ)
+	if iface == nil {
+		return nil, fmt.Errorf("fakegen: interface %s not found in %s", typ, dir)
+	}
+	imports := fileImports(file)
+	data := &fakeData{Package: pkg.name, Iface: typ, Impl: impl, Fake: "Fake" + impl}
+	used := map[string]bool{fakeImport: true}
+
+	for _, field := range iface.Methods.List {
+		ft, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) != 1 {
+			return nil, fmt.Errorf("fakegen: %s: embedded interfaces are not supported", typ)
+		}
+		m, err := newMethod(pkg, imports, used, field.Names[0].Name, ft)
+		if err != nil {
+			return nil, fmt.Errorf("fakegen: %s.%s: %w", typ, field.Names[0].Name, err)
+		}
+		data.Methods = append(data.Methods, m)
+	}
+
+	for path := range used {
+		if strings.Contains(path, ".") {
+			data.Others = append(data.Others, importSpec(imports, path))
+		} else {
+			data.Std = append(data.Std, importSpec(imports, path))
+		}
+	}
+	sort.Strings(data.Std)
+	sort.Strings(data.Others)
+
+	var buf bytes.Buffer
+	if err = fakeTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// newMethod 根据方法签名生成替身方法
+func newMethod(pkg *goPackage, imports map[string]string, used map[string]bool, name string, ft *ast.FuncType) (method, error) {
+	m := method{Name: name}
+	var params, args, record []string
+	for i, field := range ft.Params.List {
+		if err := collectImports(field.Type, imports, used); err != nil {
+			return m, err
+		}
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent("p" + strconv.Itoa(i))}
+		}
+		for _, n := range names {
+			typeText := exprString(field.Type)
+			params = append(params, n.Name+" "+typeText)
+			if _, variadic := field.Type.(*ast.Ellipsis); variadic {
+				args = append(args, n.Name+"...")
+			} else {
+				args = append(args, n.Name)
+			}
+			if typeText != "context.Context" {
+				record = append(record, n.Name)
+			}
+		}
+	}
+	m.Params = strings.Join(params, ", ")
+	m.Args = strings.Join(args, ", ")
+	if len(record) > 0 {
+		m.Record = ", " + strings.Join(record, ", ")
+	}
+
+	if ft.Results == nil {
+		return m, nil
+	}
+	var results, zeros []string
+	for _, field := range ft.Results.List {
+		if err := collectImports(field.Type, imports, used); err != nil {
+			return m, err
+		}
+		zero, err := pkg.zeroValue(field.Type, imports)
+		if err != nil {
+			return m, err
+		}
+		for n := max(len(field.Names), 1); n > 0; n-- {
+			results = append(results, exprString(field.Type))
+			zeros = append(zeros, zero)
+		}
+	}
+	m.Results = strings.Join(results, ", ")
+	if len(results) > 1 {
+		m.Results = "(" + m.Results + ")"
+	}
+	m.Zero = strings.Join(zeros, ", ")
+	return m, nil
+}
+
+// typeDef 包内声明的类型及其所在文件的导入
+type typeDef struct {
+	expr    ast.Expr
+	imports map[string]string
+}
+
+// goPackage 解析后的包，types 为包内声明的类型
+type goPackage struct {
+	name  string
+	files []*ast.File
+	types map[string]typeDef
+	root  string // 模块根目录
+}
+
+// parsePackage 解析 dir 中除测试与已生成替身以外的源码
+func parsePackage(dir string) (*goPackage, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	root, err := moduleRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	pkg := &goPackage{types: make(map[string]typeDef), root: root}
+	fset := token.NewFileSet()
+	for _, path := range matches {
+		if strings.HasSuffix(path, "_test.go") || filepath.Base(path) == outputFile {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		pkg.name = f.Name.Name
+		pkg.files = append(pkg.files, f)
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				pkg.types[ts.Name.Name] = typeDef{expr: ts.Type, imports: fileImports(f)}
+			}
+		}
+	}
+	if len(pkg.files) == 0 {
+		return nil, fmt.Errorf("fakegen: no go files in %s", dir)
+	}
+	return pkg, nil
+}
+
+// lookupInterface 查找接口声明及其所在文件
+func (p *goPackage) lookupInterface(name string) (*ast.File, *ast.InterfaceType) {
+	for _, f := range p.files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				if ts := spec.(*ast.TypeSpec); ts.Name.Name == name {
+					if it, ok := ts.Type.(*ast.InterfaceType); ok {
+						return f, it
+					}
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// zeroValue 返回类型的零值表达式，结构体指针返回 &T{}，与原有替身一致
+func (p *goPackage) zeroValue(expr ast.Expr, imports map[string]string) (string, error) {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		if under, err := p.underlying(t.X, imports); err == nil {
+			if _, ok := under.(*ast.StructType); ok {
+				return "&" + exprString(t.X) + "{}", nil
+			}
+		}
+		return "nil", nil
+	case *ast.ArrayType:
+		if t.Len != nil {
+			return exprString(t) + "{}", nil
+		}
+		return "nil", nil
+	case *ast.MapType, *ast.FuncType, *ast.ChanType, *ast.InterfaceType:
+		return "nil", nil
+	case *ast.StructType:
+		return exprString(t) + "{}", nil
+	case *ast.Ident:
+		switch t.Name {
+		case "error", "any":
+			return "nil", nil
+		case "string":
+			return `""`, nil
+		case "bool":
+			return "false", nil
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+			"float32", "float64", "byte", "rune":
+			return "0", nil
+		}
+	}
+	under, err := p.underlying(expr, imports)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := under.(*ast.StructType); ok {
+		return exprString(expr) + "{}", nil
+	}
+	return p.zeroValue(under, imports)
+}
+
+// underlying 解析本包或本模块其他包中具名类型的底层定义，别名与具名类型逐级展开
+func (p *goPackage) underlying(expr ast.Expr, imports map[string]string) (ast.Expr, error) {
+	pkg := p
+	for {
+		var (
+			def typeDef
+			ok  bool
+		)
+		switch t := expr.(type) {
+		case *ast.Ident:
+			if def, ok = pkg.types[t.Name]; !ok && types.Universe.Lookup(t.Name) != nil {
+				return expr, nil
+			}
+			if !ok {
+				return nil, fmt.Errorf("unknown type %s", t.Name)
+			}
+		case *ast.SelectorExpr:
+			x, _ := t.X.(*ast.Ident)
+			if x == nil {
+				return nil, fmt.Errorf("unsupported type %s", exprString(t))
+			}
+			path, found := imports[x.Name]
+			if !found || !strings.HasPrefix(path, modulePath+"/") {
+				return nil, fmt.Errorf("type %s is outside %s", exprString(t), modulePath)
+			}
+			other, err := parsePackage(filepath.Join(p.root, strings.TrimPrefix(path, modulePath+"/")))
+			if err != nil {
+				return nil, err
+			}
+			if def, ok = other.types[t.Sel.Name]; !ok {
+				return nil, fmt.Errorf("unknown type %s", exprString(t))
+			}
+			pkg = other
+		default:
+			return expr, nil
+		}
+		expr, imports = def.expr, def.imports
+	}
+}
+
+// fileImports 文件的导入，key 为包名
+func fileImports(f *ast.File) map[string]string {
+	imports := make(map[string]string, len(f.Imports))
+	for _, spec := range f.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = path
+	}
+	return imports
+}
+
+// collectImports 记录类型表达式引用的导入路径
+func collectImports(expr ast.Expr, imports map[string]string, used map[string]bool) (err error) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok {
+			path, found := imports[x.Name]
+			if !found {
+				err = fmt.Errorf("import of %s not found", x.Name)
+				return false
+			}
+			used[path] = true
+		}
+		return false
+	})
+	return err
+}
+
+// importSpec 导入声明，包名与路径末段不同时保留别名
+func importSpec(imports map[string]string, path string) string {
+	for name, p := range imports {
+		if p == path && name != path[strings.LastIndex(path, "/")+1:] {
+			return name + " " + strconv.Quote(path)
+		}
+	}
+	return strconv.Quote(path)
+}
+
+// moduleRoot 向上查找 go.mod 所在目录
+func moduleRoot(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for d := abs; ; d = filepath.Dir(d) {
+		if _, err = os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			return d, nil
+		}
+		if filepath.Dir(d) == d {
+			return "", fmt.Errorf("fakegen: go.mod not found above %s", abs)
+		}
+	}
+}
+
+// exprString 类型表达式的源码
+func exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	_ = format.Node(&buf, token.NewFileSet(), expr)
+	return buf.String()
+}
+
+var fakeTemplate = template.Must(template.New("fake").Parse(`/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+// Code generated by fakegen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .Std}}
+	{{.}}
+{{- end}}
+{{if .Std}}
+{{end -}}
+{{- range .Others}}
+	{{.}}
+{{- end}}
+)
+
+var (
+	_ {{.Iface}} = (*{{.Impl}})(nil)
+	_ {{.Iface}} = (*{{.Fake}})(nil)
+)
+
+// {{.Fake}} {{.Iface}} 的测试替身，记录调用并返回 XxxFunc 预设的结果，未设置时返回零值
+type {{.Fake}} struct {
+	fake.Recorder
+
+{{range .Methods}}
+	{{.Name}}Func func({{.Params}}) {{.Results}}
+{{- end}}
+}
+{{range .Methods}}
+// {{.Name}} implements {{$.Iface}}
+func (f *{{$.Fake}}) {{.Name}}({{.Params}}) {{.Results}} {
+	f.Record("{{.Name}}"{{.Record}})
+	if f.{{.Name}}Func != nil {
+		{{if .Results}}return {{end}}f.{{.Name}}Func({{.Args}})
+	{{- if not .Results}}
+		return
+	{{- end}}
+	}
+	{{- if .Results}}
+	return {{.Zero}}
+	{{- end}}
+}
+{{end}}`))
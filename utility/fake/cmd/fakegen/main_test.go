@@ -0,0 +1,89 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// directive 匹配接口文件中的 go:generate 声明
+var directive = regexp.MustCompile(`(?m)^//go:generate go run ` + regexp.QuoteMeta(modulePath) + `/utility/fake/cmd/fakegen -type (\w+) -impl (\w+)$`)
+
+// TestFakesUpToDate 确保已提交的替身与接口一致，且每个替身都由 fakegen 生成
+func TestFakesUpToDate(t *testing.T) {
+	root, err := moduleRoot(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated := make(map[string]bool)
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, m := range directive.FindAllSubmatch(src, -1) {
+			dir := filepath.Dir(path)
+			got, err := generate(dir, string(m[1]), string(m[2]))
+			if err != nil {
+				t.Errorf("generate(%s, %s) error = %v", dir, m[1], err)
+				continue
+			}
+			out := filepath.Join(dir, outputFile)
+			want, err := os.ReadFile(out)
+			if err != nil {
+				t.Errorf("read %s error = %v", out, err)
+				continue
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s is out of date with %s, run go generate ./...", out, m[1])
+			}
+			generated[out] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() != outputFile {
+			return err
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(src, []byte("fake.Recorder")) && !generated[path] {
+			t.Errorf("%s has no fakegen go:generate directive", path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+// Package fake 服务接口测试替身的公共部分，记录方法调用供测试断言
+package fake
+
+import "sync"
+
+// Call 一次方法调用
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// Recorder 记录方法调用，零值可直接使用，并发安全
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Record 记录一次调用
+func (r *Recorder) Record(method string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls 返回全部调用记录
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsOf 返回指定方法的调用记录
+func (r *Recorder) CallsOf(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// Count 返回指定方法的调用次数
+func (r *Recorder) Count(method string) int {
+	return len(r.CallsOf(method))
+}
+
+// Reset 清空调用记录
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
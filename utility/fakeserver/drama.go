@@ -0,0 +1,438 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package fakeserver
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/houseme/bytedance/minidrama/drama"
+)
+
+// album 短剧，审核与上线状态按版本推进
+type album struct {
+	id            int64
+	version       int
+	status        int
+	auditStatus   int
+	onlineVersion int
+	info          *drama.AlbumInfo
+	episodes      map[int]*episode
+	apps          []string
+}
+
+// episode 剧集
+type episode struct {
+	id          int64
+	info        *drama.EpisodeInfo
+	version     int
+	status      int
+	auditStatus int
+}
+
+// video 内容库视频
+type video struct {
+	openVideoID string
+	dyCloudID   string
+	status      string
+}
+
+// nextNum 生成递增的数字 ID，调用方需持有锁
+func (s *Server) nextNum() int64 {
+	s.seq++
+	return 7000000000000000000 + s.seq
+}
+
+// AlbumAuditStatus 返回短剧当前版本与审核状态，供测试断言
+func (s *Server) AlbumAuditStatus(albumID int64) (version, status, auditStatus int, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.albums[albumID]
+	if !ok {
+		return 0, 0, 0, false
+	}
+	return a.version, a.status, a.auditStatus, true
+}
+
+// SetAlbumAuditStatus 设置短剧及其剧集的审核结果，例如模拟审核不通过
+func (s *Server) SetAlbumAuditStatus(albumID int64, auditStatus int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.albums[albumID]
+	if !ok {
+		return false
+	}
+	a.status, a.auditStatus = drama.StatusReview, auditStatus
+	for _, e := range a.episodes {
+		e.status, e.auditStatus = drama.StatusReview, auditStatus
+	}
+	return true
+}
+
+// registerDrama 注册短剧相关接口
+func (s *Server) registerDrama() {
+	s.handle("/api/playlet/v2/resource/upload", true, s.resourceUpload)
+	s.handle("/api/playlet/v2/video/query", true, s.videoQuery)
+	s.handle("/api/playlet/v2/video/create", true, s.albumCreate)
+	s.handle("/api/playlet/v2/video/edit", true, s.albumEdit)
+	s.handle("/api/playlet/v2/video/review", true, s.albumReview)
+	s.handle("/api/playlet/v2/album/fetch", true, s.albumFetch)
+	s.handle("/api/playlet/v2/auth/authorize", true, s.albumAuthorize)
+	s.handle("/api/playlet/v2/album/online", true, s.albumOnline)
+	s.handle("/api/playlet/v2/album/bind", true, s.albumBind)
+	s.handle("/api/playlet/v2/video/play_info", true, s.videoPlayInfo)
+}
+
+func (s *Server) resourceUpload(_ *http.Request, body []byte) any {
+	var req struct {
+		ResourceType int              `json:"resource_type"`
+		ImageMeta    *drama.ImageMeta `json:"image_meta"`
+		VideoMeta    *drama.VideoMeta `json:"video_meta"`
+	}
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	switch req.ResourceType {
+	case drama.ResourceTypeImage:
+		if req.ImageMeta == nil || req.ImageMeta.URL == "" {
+			return errResponse(ErrNoInvalidParam, "image_meta.url is empty")
+		}
+		return okResponse(&drama.ImageData{
+			ResourceType: drama.ResourceTypeImage,
+			ImageResult:  &drama.ImageResult{OpenPicID: s.nextID("pic")},
+		})
+	case drama.ResourceTypeVideo:
+		if req.VideoMeta == nil || (req.VideoMeta.URL == "" && req.VideoMeta.DyCloudID == "") {
+			return errResponse(ErrNoInvalidParam, "video_meta.url is empty")
+		}
+		v := &video{openVideoID: s.nextID("vid"), dyCloudID: req.VideoMeta.DyCloudID, status: "success"}
+		if v.dyCloudID == "" && req.VideoMeta.UseDyCloud {
+			v.dyCloudID = s.nextID("dyc")
+		}
+		s.videos[v.openVideoID] = v
+		return okResponse(&drama.VideoData{
+			ResourceType: drama.ResourceTypeVideo,
+			VideoResult:  &drama.VideoResult{OpenVideoID: v.openVideoID},
+		})
+	}
+	return errResponse(ErrNoInvalidParam, "resource_type is invalid")
+}
+
+func (s *Server) videoQuery(_ *http.Request, body []byte) any {
+	var req drama.QueryVideoRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	for _, v := range s.videos {
+		if (req.OpenVideoID != "" && v.openVideoID == req.OpenVideoID) || (req.DyCloudID != "" && v.dyCloudID == req.DyCloudID) {
+			return okResponse(&drama.QueryVideoData{OpenVideoID: v.openVideoID, Status: v.status, DyCloudID: v.dyCloudID})
+		}
+	}
+	return errResponse(ErrNoNotFound, "video not exist")
+}
+
+func (s *Server) albumCreate(_ *http.Request, body []byte) any {
+	var req drama.CreateVideoRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	if req.AlbumInfo == nil || req.AlbumInfo.Title == "" {
+		return errResponse(ErrNoInvalidParam, "album_info.title is empty")
+	}
+	a := &album{
+		id:          s.nextNum(),
+		status:      drama.StatusNot,
+		auditStatus: drama.AuditStatusNot,
+		info:        req.AlbumInfo,
+		episodes:    make(map[int]*episode),
+	}
+	s.albums[a.id] = a
+	return okResponse(&drama.CreateVideoData{AlbumID: a.id})
+}
+
+func (s *Server) albumEdit(_ *http.Request, body []byte) any {
+	var req drama.EditVideoRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	a, ok := s.albums[req.AlbumID]
+	if !ok {
+		return errResponse(ErrNoNotFound, "album not exist")
+	}
+	if a.status == drama.StatusSubmit {
+		return errResponse(ErrNoInvalidState, "album is under review")
+	}
+	// 审核后再编辑版本会增加
+	if a.status == drama.StatusReview {
+		a.version++
+		a.status, a.auditStatus = drama.StatusNot, drama.AuditStatusNot
+	}
+	if req.AlbumInfo != nil {
+		a.info = req.AlbumInfo
+	}
+	ids := make(map[string]uint64, len(req.EpisodeInfoList))
+	for _, info := range req.EpisodeInfoList {
+		if info.OpenVideoID != "" {
+			if _, ok := s.videos[info.OpenVideoID]; !ok {
+				return errResponse(ErrNoNotFound, "open_video_id not exist: "+info.OpenVideoID)
+			}
+		}
+		e, ok := a.episodes[info.Seq]
+		if !ok {
+			e = &episode{id: s.nextNum()}
+			a.episodes[info.Seq] = e
+		}
+		e.info, e.version = info, a.version
+		e.status, e.auditStatus = drama.StatusNot, drama.AuditStatusNot
+		ids[fmt.Sprint(info.Seq)] = uint64(e.id)
+	}
+	return okResponse(&drama.EditVideoData{AlbumID: a.id, Version: a.version, EpisodeIdMap: ids})
+}
+
+func (s *Server) albumReview(_ *http.Request, body []byte) any {
+	var req drama.ReviewVideoRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	a, ok := s.albums[req.AlbumID]
+	if !ok {
+		return errResponse(ErrNoNotFound, "album not exist")
+	}
+	if len(a.episodes) == 0 {
+		return errResponse(ErrNoInvalidState, "album has no episode")
+	}
+	a.status, a.auditStatus = drama.StatusSubmit, drama.AuditStatusReviewing
+	for _, e := range a.episodes {
+		e.status, e.auditStatus = drama.StatusSubmit, drama.AuditStatusReviewing
+	}
+	return okResponse(nil)
+}
+
+// advance 审核中的短剧在被查询后审核通过
+func (a *album) advance() {
+	if a.auditStatus != drama.AuditStatusReviewing {
+		return
+	}
+	a.status, a.auditStatus = drama.StatusReview, drama.AuditStatusPass
+	for _, e := range a.episodes {
+		e.status, e.auditStatus = drama.StatusReview, drama.AuditStatusPass
+	}
+}
+
+// albumResp 转换为接口返回结构
+func (a *album) albumResp() *drama.AlbumInfoResp {
+	resp := &drama.AlbumInfoResp{
+		AlbumStatus:    a.info.AlbumStatus,
+		Recommendation: a.info.Recommendation,
+		SeqNum:         a.info.SeqNum,
+		Title:          a.info.Title,
+		CoverList:      a.info.CoverList,
+		TagList:        a.info.TagList,
+		Year:           a.info.Year,
+		Qualification:  a.info.Qualification,
+		Desp:           a.info.Desp,
+		AlbumAuditInfo: &drama.AlbumAuditInfo{
+			Status:      a.status,
+			AlbumID:     a.id,
+			Version:     a.version,
+			AuditStatus: a.auditStatus,
+		},
+	}
+	if a.auditStatus == drama.AuditStatusPass {
+		resp.AlbumAuditInfo.ScopeList = []string{"播放", "投广", "挂载"}
+	}
+	if r := a.info.RecordInfo; r != nil {
+		resp.RecordInfo = &drama.RecordInfoResp{
+			LicenseNum:        r.LicenseNum,
+			RegistrationNum:   r.RegistrationNum,
+			OrdinaryRecordNum: r.OrdinaryRecordNum,
+			KeyRecordNum:      r.KeyRecordNum,
+		}
+	}
+	return resp
+}
+
+// page 按 offset 与 limit 截取
+func page(total, offset, limit int) (int, int) {
+	if limit <= 0 || limit > drama.LimitMax {
+		limit = drama.LimitMax
+	}
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return offset, end
+}
+
+func (s *Server) albumFetch(_ *http.Request, body []byte) any {
+	var req drama.QueryVideoAlbumRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	for _, a := range s.albums {
+		a.advance()
+	}
+	switch req.QueryType {
+	case drama.QueryTypeAll:
+		if req.BatchQuery == nil {
+			return errResponse(ErrNoInvalidParam, "batch_query is empty")
+		}
+		ids := make([]int64, 0, len(s.albums))
+		for id := range s.albums {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		start, end := page(len(ids), req.BatchQuery.Offset, req.BatchQuery.Limit)
+		list := make([]*drama.AlbumInfoResp, 0, end-start)
+		for _, id := range ids[start:end] {
+			list = append(list, s.albums[id].albumResp())
+		}
+		return okResponse(&drama.QueryVideoAlbumData{BatchData: &drama.BatchData{Total: len(ids), AlbumInfoList: list}})
+	case drama.QueryTypeOne:
+		if req.SingleQuery == nil {
+			return errResponse(ErrNoInvalidParam, "single_query is empty")
+		}
+		a, ok := s.albums[req.SingleQuery.AlbumID]
+		if !ok {
+			return errResponse(ErrNoNotFound, "album not exist")
+		}
+		return okResponse(&drama.QueryVideoAlbumData{SingleData: &drama.SingleData{Total: 1, AlbumInfoList: []*drama.AlbumInfoResp{a.albumResp()}}})
+	case drama.QueryTypeOneVersion:
+		if req.DetailQuery == nil {
+			return errResponse(ErrNoInvalidParam, "detail_query is empty")
+		}
+		a, ok := s.albums[req.DetailQuery.AlbumID]
+		if !ok {
+			return errResponse(ErrNoNotFound, "album not exist")
+		}
+		seqs := make([]int, 0, len(a.episodes))
+		for seq, e := range a.episodes {
+			if e.version <= req.DetailQuery.Version {
+				seqs = append(seqs, seq)
+			}
+		}
+		sort.Ints(seqs)
+		start, end := page(len(seqs), req.DetailQuery.Offset, req.DetailQuery.Limit)
+		list := make([]*drama.EpisodeInfoResp, 0, end-start)
+		for _, seq := range seqs[start:end] {
+			e := a.episodes[seq]
+			list = append(list, &drama.EpisodeInfoResp{
+				Title:       e.info.Title,
+				CoverList:   e.info.CoverList,
+				OpenVideoID: e.info.OpenVideoID,
+				Seq:         seq,
+				EpisodeAuditInfo: &drama.EpisodeAuditInfo{
+					EpisodeID:   e.id,
+					Version:     e.version,
+					Status:      e.status,
+					AuditStatus: e.auditStatus,
+				},
+			})
+		}
+		return okResponse(&drama.QueryVideoAlbumData{DetailData: &drama.DetailData{Total: len(seqs), EpisodeInfoList: list}})
+	}
+	return errResponse(ErrNoInvalidParam, "query_type is invalid")
+}
+
+func (s *Server) albumAuthorize(_ *http.Request, body []byte) any {
+	var req drama.AuthorizeVideoRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	a, ok := s.albums[req.AlbumID]
+	if !ok {
+		return errResponse(ErrNoNotFound, "album not exist")
+	}
+	a.apps = append(a.apps, req.AppIdList...)
+	return okResponse(nil)
+}
+
+func (s *Server) albumOnline(_ *http.Request, body []byte) any {
+	var req drama.OnlineAlbumRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	a, ok := s.albums[req.AlbumID]
+	if !ok {
+		return errResponse(ErrNoNotFound, "album not exist")
+	}
+	if req.Operate == 2 {
+		if req.Version > a.version || (req.Version == a.version && a.auditStatus != drama.AuditStatusPass) {
+			return errResponse(ErrNoInvalidState, "version is not audited")
+		}
+		a.onlineVersion = req.Version
+	}
+	return okResponse(&drama.OnlineAlbumData{AlbumID: a.id, Version: a.onlineVersion})
+}
+
+func (s *Server) albumBind(_ *http.Request, body []byte) any {
+	var req drama.BindAlbumRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	if req.SingleSchemaBind == nil {
+		return errResponse(ErrNoInvalidParam, "single_schema_bind is empty")
+	}
+	if _, ok := s.episode(req.SingleSchemaBind.AlbumID, req.SingleSchemaBind.EpisodeID); !ok {
+		return errResponse(ErrNoNotFound, "episode not exist")
+	}
+	return okResponse(nil)
+}
+
+func (s *Server) videoPlayInfo(_ *http.Request, body []byte) any {
+	var req drama.PlayInfoRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	e, ok := s.episode(req.AlbumID, req.EpisodeID)
+	if !ok {
+		return errResponse(ErrNoNotFound, "episode not exist")
+	}
+	if e.auditStatus != drama.AuditStatusPass {
+		return errResponse(ErrNoInvalidState, "episode is not playable")
+	}
+	return okResponse(&drama.PlayInfoData{
+		Definition: "720p",
+		Format:     "mp4",
+		PlayURL:    s.URL + "/play/" + e.info.OpenVideoID + ".mp4",
+		Size:       1024,
+		UrlExpire:  "3600",
+		Bitrate:    1000,
+		Codec:      "h264",
+	})
+}
+
+// episode 查找剧集，调用方需持有锁
+func (s *Server) episode(albumID, episodeID int64) (*episode, bool) {
+	a, ok := s.albums[albumID]
+	if !ok {
+		return nil, false
+	}
+	for _, e := range a.episodes {
+		if e.id == episodeID {
+			return e, true
+		}
+	}
+	return nil, false
+}
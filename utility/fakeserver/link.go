@@ -0,0 +1,185 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package fakeserver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/houseme/bytedance/miniprogram/link"
+	"github.com/houseme/bytedance/miniprogram/schema"
+)
+
+const (
+	// linkQuotaLimit 每日 url_link 生成上限
+	linkQuotaLimit = 100000
+	// schemaQuotaLimit 每日 schema 生成上限
+	schemaQuotaLimit = 100000
+	// defaultExpireDays 未指定失效时间时的默认有效期
+	defaultExpireDays = 30
+)
+
+// linkInfo 已生成的 url_link 或 schema
+type linkInfo struct {
+	appID      string
+	appName    string
+	path       string
+	query      string
+	noExpire   bool
+	createTime int
+	expireTime int
+}
+
+// registerLink 注册 url_link 与 schema 接口，SDK 不携带 access_token，因此不校验鉴权
+func (s *Server) registerLink() {
+	s.handle("/api/apps/url_link/generate", false, s.linkGenerateV1)
+	s.handle("/api/apps/v1/url_link/generate", false, s.linkGenerate)
+	s.handle("/api/apps/v1/url_link/query_info", false, s.linkQuery)
+	s.handle("/api/apps/v1/url_link/query_quota", false, s.linkQuota)
+	s.handle("/api/apps/v1/url/generate_schema", false, s.schemaGenerate)
+	s.handle("/api/apps/v1/url/query_schema", false, s.schemaQuery)
+	s.handle("/api/apps/v1/url/query_schema_quota", false, s.schemaQuota)
+}
+
+// newLinkInfo 创建链接信息，expireTime 为 0 时使用默认有效期
+func newLinkInfo(appID, appName, path, query string, expireTime int) *linkInfo {
+	now := time.Now()
+	if expireTime == 0 {
+		expireTime = int(now.AddDate(0, 0, defaultExpireDays).Unix())
+	}
+	return &linkInfo{appID: appID, appName: appName, path: path, query: query, createTime: int(now.Unix()), expireTime: expireTime}
+}
+
+func (s *Server) linkGenerateV1(_ *http.Request, body []byte) any {
+	var req link.GenerateV1Request
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	if _, ok := s.tokens[req.AccessToken]; !ok {
+		return map[string]any{"err_no": ErrNoInvalidAccessToken, "err_tips": "access token is invalid"}
+	}
+	url := "https://z.douyin.com/" + s.nextID("l")
+	s.links[url] = newLinkInfo(req.MaAppID, req.AppName, req.Path, req.Query, req.ExpireTime)
+	return &link.GenerateV1Response{ErrNo: ErrNoSuccess, ErrTips: "success", URLLink: url}
+}
+
+func (s *Server) linkGenerate(_ *http.Request, body []byte) any {
+	var req link.GenerateLinkRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	if req.AppID == "" {
+		return errResponse(ErrNoInvalidParam, "app_id is empty")
+	}
+	url := "https://z.douyin.com/" + s.nextID("l")
+	s.links[url] = newLinkInfo(req.AppID, req.AppName, req.Path, req.Query, req.ExpireTime)
+	return okResponse(&link.GenerateLinkData{URLLink: url})
+}
+
+func (s *Server) linkQuery(_ *http.Request, body []byte) any {
+	var req link.QueryLinkRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	l, ok := s.links[req.URLLink]
+	if !ok || l.appID != req.AppID {
+		return errResponse(ErrNoNotFound, "url_link not exist")
+	}
+	return okResponse(&link.QueryLinkData{
+		AppName:    l.appName,
+		AppID:      l.appID,
+		Path:       l.path,
+		Query:      l.query,
+		CreateTime: l.createTime,
+		ExpireTime: l.expireTime,
+	})
+}
+
+func (s *Server) linkQuota(_ *http.Request, body []byte) any {
+	var req link.QueryLinkQuotaRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	var used int
+	for _, l := range s.links {
+		if l.appID == req.AppID {
+			used++
+		}
+	}
+	resp := okResponse(nil)
+	resp["url_link_quota"] = &link.URLLinkQuota{URLLinkUsed: used, URLLinkLimit: linkQuotaLimit}
+	return resp
+}
+
+func (s *Server) schemaGenerate(_ *http.Request, body []byte) any {
+	var req schema.GenerateSchemaRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	if req.AppID == "" {
+		return errResponse(ErrNoInvalidParam, "app_id is empty")
+	}
+	sch := "sslocal://miniapp?ticket=" + s.nextID("t")
+	info := newLinkInfo(req.AppID, "", req.Path, req.Query, req.ExpireTime)
+	if req.NoExpire {
+		info.noExpire, info.expireTime = true, 0
+	}
+	s.schemas[sch] = info
+	return okResponse(&schema.GenerateSchemaData{Schema: sch})
+}
+
+func (s *Server) schemaQuery(_ *http.Request, body []byte) any {
+	var req schema.QuerySchemaRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	l, ok := s.schemas[req.Schema]
+	if !ok || l.appID != req.AppID {
+		return errResponse(ErrNoNotFound, "schema not exist")
+	}
+	return okResponse(&schema.QuerySchemaData{
+		AppID:      l.appID,
+		Path:       l.path,
+		Query:      l.query,
+		CreateTime: l.createTime,
+		ExpireTime: l.expireTime,
+	})
+}
+
+func (s *Server) schemaQuota(_ *http.Request, body []byte) any {
+	var req schema.QuerySchemaQuotaRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	var data schema.QuerySchemaQuotaData
+	data.LongTermSchemaQuota.SchemaLimit = schemaQuotaLimit
+	data.ShortTermSchemaQuota.SchemaLimit = schemaQuotaLimit
+	for _, l := range s.schemas {
+		if l.appID != req.AppID {
+			continue
+		}
+		if l.noExpire {
+			data.LongTermSchemaQuota.SchemaUsed++
+		} else {
+			data.ShortTermSchemaQuota.SchemaUsed++
+		}
+	}
+	return okResponse(&data)
+}
@@ -0,0 +1,350 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package fakeserver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/houseme/bytedance/pay/refund"
+	"github.com/houseme/bytedance/pay/settle"
+	"github.com/houseme/bytedance/pay/trade"
+	"github.com/houseme/bytedance/pay/withdraw"
+)
+
+// order 交易订单
+type order struct {
+	data *trade.QueryOrderData
+}
+
+// settleOrder 分账单，首次查询返回 PROCESSING，之后变为 SUCCESS
+type settleOrder struct {
+	data *settle.QuerySettleData
+}
+
+// refundOrder 退款单，首次查询返回 PROCESSING，之后变为 SUCCESS
+type refundOrder struct {
+	data *refund.RefundInfo
+}
+
+// balance 商户余额
+type balance struct {
+	info withdraw.AccountInfo
+}
+
+// withdrawOrder 提现单，首次查询返回 PROCESSING，之后变为 SUCCESS
+type withdrawOrder struct {
+	orderID string
+	status  string
+	pinned  bool
+}
+
+// AddOrder 添加一笔交易订单，可通过 order_id 或 out_order_no 查询
+func (s *Server) AddOrder(data *trade.QueryOrderData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if data.OrderID == "" {
+		data.OrderID = s.nextID("ord")
+	}
+	if data.PayStatus == "" {
+		data.PayStatus = "SUCCESS"
+	}
+	o := &order{data: data}
+	s.orders[data.OrderID] = o
+	if data.OutOrderNo != "" {
+		s.orders[data.OutOrderNo] = o
+	}
+}
+
+// SetBalance 设置商户在某个提现渠道的余额
+func (s *Server) SetBalance(merchantUID, channelType string, info withdraw.AccountInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balances[merchantUID+"|"+channelType] = &balance{info: info}
+}
+
+// SetWithdrawStatus 设置提现单状态，例如模拟 REEXCHANGE 退票，设置后不再自动推进
+func (s *Server) SetWithdrawStatus(outOrderID, status string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.withdraws[outOrderID]
+	if ok {
+		w.status = status
+		w.pinned = true
+	}
+	return ok
+}
+
+// tagCatalog 各商品类型可用的标签组
+var tagCatalog = map[int][]*trade.TagGroup{
+	trade.GoodsTypeNumberCard:   {{TagGroupID: trade.NumberCardCommodityTagID, TagGroupName: "号卡商品"}},
+	trade.GoodsTypeCustomized:   {{TagGroupID: trade.CustomizedServiceRefundTagID, TagGroupName: "定制后协商退"}, {TagGroupID: trade.CustomizedServiceNoRefundTagID, TagGroupName: "定制后不可退"}},
+	trade.GoodsTypeRecharge:     {{TagGroupID: trade.VirtualRechargeTagID, TagGroupName: "虚拟充值"}},
+	trade.GoodsTypeConsultation: {{TagGroupID: trade.GeneralConsultationRefundTagID, TagGroupName: "开始服务后协商退"}, {TagGroupID: trade.GeneralConsultationNoRefundTagID, TagGroupName: "开始服务后不可退"}},
+	trade.GoodsTypeGhostwriting: {{TagGroupID: trade.GhostwritingDocumentTagID, TagGroupName: "代写文书"}},
+	trade.GoodsTypeVirtualTool:  {{TagGroupID: trade.VirtualServiceTagID, TagGroupName: "虚拟服务"}},
+	trade.GoodsTypeContent:      {{TagGroupID: trade.ContentRechargeTagID, TagGroupName: "内容充值"}},
+}
+
+// SetTagGroups 覆盖商品类型可用的标签组，groups 为空时模拟平台返回空列表
+func (s *Server) SetTagGroups(goodsType int, groups []*trade.TagGroup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tags[goodsType] = groups
+}
+
+// registerPay 注册交易、分账与提现接口
+func (s *Server) registerPay() {
+	for goodsType, groups := range tagCatalog {
+		s.tags[goodsType] = groups
+	}
+	s.handle("/api/trade_basic/v1/developer/order_query", true, s.orderQuery)
+	s.handle("/api/trade_basic/v1/developer/tag_query", true, s.tagQuery)
+	s.handle("/api/trade_basic/v1/developer/settle_create", true, s.settleCreate)
+	s.handle("/api/trade_basic/v1/developer/settle_query", true, s.settleQuery)
+	s.handle("/api/trade_basic/v1/developer/refund_create", true, s.refundCreate)
+	s.handle("/api/trade_basic/v1/developer/refund_query", true, s.refundQuery)
+	s.handle("/api/apps/ecpay/saas/query_merchant_balance", true, s.queryBalance)
+	s.handle("/api/apps/ecpay/saas/merchant_withdraw", true, s.merchantWithdraw)
+	s.handle("/api/apps/ecpay/saas/query_withdraw_order", true, s.queryWithdraw)
+}
+
+func (s *Server) orderQuery(_ *http.Request, body []byte) any {
+	var req trade.QueryOrderRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	key := req.OrderID
+	if key == "" {
+		key = req.OutOrderNo
+	}
+	o, ok := s.orders[key]
+	if !ok {
+		return errResponse(ErrNoNotFound, "order not exist")
+	}
+	return okResponse(o.data)
+}
+
+func (s *Server) tagQuery(_ *http.Request, body []byte) any {
+	var req trade.QueryTagRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	groups, ok := s.tags[req.GoodsType]
+	if !ok {
+		return errResponse(ErrNoInvalidParam, "goods_type is invalid")
+	}
+	return okResponse(&trade.QueryTagData{TagList: groups})
+}
+
+func (s *Server) settleCreate(_ *http.Request, body []byte) any {
+	var req settle.ApplySettleRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	o, ok := s.orders[req.OutOrderNo]
+	if !ok {
+		return errResponse(ErrNoNotFound, "order not exist")
+	}
+	if req.OutSettleNo == "" {
+		return errResponse(ErrNoInvalidParam, "out_settle_no is empty")
+	}
+	for _, st := range s.settles {
+		if st.data.OutSettleID == req.OutSettleNo {
+			return okResponse(&settle.ApplySettleData{SettleID: st.data.SettleID, WalletSettleID: "w" + st.data.SettleID})
+		}
+	}
+
+	amount := o.data.TotalAmount - o.data.DiscountAmount
+	if req.ItemOrderID != "" {
+		amount = 0
+		for _, item := range o.data.ItemOrderList {
+			if item.ItemOrderID == req.ItemOrderID {
+				amount = item.ItemOrderAmount
+			}
+		}
+	}
+	st := &settleOrder{data: &settle.QuerySettleData{
+		SettleAmount: amount,
+		CpExtra:      req.Ext,
+		OutSettleID:  req.OutSettleNo,
+		OrderID:      o.data.OrderID,
+		OutOrderID:   o.data.OutOrderNo,
+		SettleAt:     time.Now().Unix(),
+		SettleDetail: req.SettleParams,
+		ItemOrderID:  req.ItemOrderID,
+		SettleID:     s.nextID("stl"),
+		SettleStatus: settle.StateProcessing,
+	}}
+	s.settles = append(s.settles, st)
+	return okResponse(&settle.ApplySettleData{SettleID: st.data.SettleID, WalletSettleID: "w" + st.data.SettleID})
+}
+
+func (s *Server) settleQuery(_ *http.Request, body []byte) any {
+	var req settle.QuerySettleRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	list := make([]*settle.QuerySettleData, 0)
+	for _, st := range s.settles {
+		var hit bool
+		switch {
+		case req.SettleID != "":
+			hit = st.data.SettleID == req.SettleID
+		case req.OrderID != "":
+			hit = st.data.OrderID == req.OrderID
+		case req.OutSettleNo != "":
+			hit = st.data.OutSettleID == req.OutSettleNo
+		case req.OutOrderNo != "":
+			hit = st.data.OutOrderID == req.OutOrderNo
+		}
+		if !hit {
+			continue
+		}
+		data := *st.data
+		list = append(list, &data)
+		if st.data.SettleStatus == settle.StateProcessing {
+			st.data.SettleStatus = settle.StateSuccess
+		}
+	}
+	return okResponse(list)
+}
+
+func (s *Server) refundCreate(_ *http.Request, body []byte) any {
+	var req refund.CreateRefundRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	o, ok := s.orders[req.OutOrderNo]
+	if !ok {
+		return errResponse(ErrNoNotFound, "order not exist")
+	}
+	if req.OutRefundNo == "" || req.RefundTotalAmount <= 0 {
+		return errResponse(ErrNoInvalidParam, "out_refund_no or refund_total_amount is invalid")
+	}
+	for _, r := range s.refunds {
+		if r.data.OutRefundNo == req.OutRefundNo {
+			return okResponse(&refund.CreateRefundData{RefundID: r.data.RefundID})
+		}
+	}
+	r := &refundOrder{data: &refund.RefundInfo{
+		RefundID:          s.nextID("rfd"),
+		OutRefundNo:       req.OutRefundNo,
+		OrderID:           o.data.OrderID,
+		RefundStatus:      refund.StateProcessing,
+		RefundTotalAmount: req.RefundTotalAmount,
+		CpExtra:           req.CpExtra,
+		CreateAt:          time.Now().UnixMilli(),
+		ItemOrderDetail:   req.ItemOrderDetail,
+	}}
+	s.refunds = append(s.refunds, r)
+	return okResponse(&refund.CreateRefundData{RefundID: r.data.RefundID})
+}
+
+func (s *Server) refundQuery(_ *http.Request, body []byte) any {
+	var req refund.QueryRefundRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	list := make([]*refund.RefundInfo, 0)
+	for _, r := range s.refunds {
+		var hit bool
+		switch {
+		case req.RefundID != "":
+			hit = r.data.RefundID == req.RefundID
+		case req.OutRefundNo != "":
+			hit = r.data.OutRefundNo == req.OutRefundNo
+		case req.OrderID != "":
+			hit = r.data.OrderID == req.OrderID
+		}
+		if !hit {
+			continue
+		}
+		data := *r.data
+		list = append(list, &data)
+		if r.data.RefundStatus == refund.StateProcessing {
+			r.data.RefundStatus = refund.StateSuccess
+			r.data.RefundAt = time.Now().UnixMilli()
+		}
+	}
+	return okResponse(&refund.QueryRefundData{RefundList: list})
+}
+
+func (s *Server) queryBalance(_ *http.Request, body []byte) any {
+	var req withdraw.QueryBalanceRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	b, ok := s.balances[req.MerchantUID+"|"+req.ChannelType]
+	if !ok {
+		return errResponse(ErrNoNotFound, "merchant account not exist")
+	}
+	entity := req.MerchantEntity
+	if entity == withdraw.MerchantEntityDefault {
+		entity = withdraw.MerchantEntityDy
+	}
+	return okResponse(&withdraw.QueryBalanceData{
+		AccountInfo:    b.info,
+		MerchantEntity: entity,
+	})
+}
+
+func (s *Server) merchantWithdraw(_ *http.Request, body []byte) any {
+	var req withdraw.MerchantWithdrawRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	if req.OutOrderID == "" || req.WithdrawAmount <= 0 {
+		return errResponse(ErrNoInvalidParam, "out_order_id or withdraw_amount is invalid")
+	}
+	if w, ok := s.withdraws[req.OutOrderID]; ok {
+		return okResponse(&withdraw.MerchantWithdrawData{OrderID: w.orderID, MerchantEntity: withdraw.MerchantEntityDy})
+	}
+	b, ok := s.balances[req.MerchantUID+"|"+req.ChannelType]
+	if !ok {
+		return errResponse(ErrNoNotFound, "merchant account not exist")
+	}
+	if b.info.WithdrawAbleBalance < req.WithdrawAmount {
+		return errResponse(ErrNoInsufficientBalance, "withdrawable balance is insufficient")
+	}
+	b.info.WithdrawAbleBalance -= req.WithdrawAmount
+	w := &withdrawOrder{
+		orderID: s.nextID("wd"),
+		status:  withdraw.StateProcessing,
+	}
+	s.withdraws[req.OutOrderID] = w
+	return okResponse(&withdraw.MerchantWithdrawData{OrderID: w.orderID, MerchantEntity: withdraw.MerchantEntityDy})
+}
+
+func (s *Server) queryWithdraw(_ *http.Request, body []byte) any {
+	var req withdraw.QueryMerchantWithdrawRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	w, ok := s.withdraws[req.OutOrderID]
+	if !ok {
+		return errResponse(ErrNoNotFound, "withdraw order not exist")
+	}
+	data := &withdraw.QueryMerchantWithdrawData{Status: w.status, StatusMsg: w.status}
+	if !w.pinned && w.status == withdraw.StateProcessing {
+		w.status = withdraw.StateSuccess
+	}
+	return okResponse(data)
+}
@@ -0,0 +1,292 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+// Package fakeserver 基于 httptest 的抖音开放平台模拟服务，用于离线运行集成测试
+//
+// 模拟服务按请求路径路由，忽略域名，配合 config.WithBaseURL 使用：
+//
+//	srv := fakeserver.New()
+//	defer srv.Close()
+//	cfg := config.New(ctx, config.WithBaseURL(srv.URL), config.WithCache(...), ...)
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/houseme/bytedance/pay/trade"
+)
+
+// 模拟服务自定义的 err_no，仅用于测试断言，不对应平台的真实错误码
+const (
+	// ErrNoSuccess 成功
+	ErrNoSuccess = 0
+	// ErrNoInvalidParam 参数错误
+	ErrNoInvalidParam = 28001001
+	// ErrNoInvalidAccessToken access_token 无效
+	ErrNoInvalidAccessToken = 28001003
+	// ErrNoNotFound 资源不存在
+	ErrNoNotFound = 28001004
+	// ErrNoInsufficientBalance 可提现余额不足
+	ErrNoInsufficientBalance = 28001005
+	// ErrNoInvalidState 当前状态不允许该操作
+	ErrNoInvalidState = 28001006
+
+	// tokenExpiresIn 下发 token 的有效期，需要大于 SDK 缓存时扣除的 1500 秒
+	tokenExpiresIn = 7200
+)
+
+// RecordedRequest 模拟服务收到的请求
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Body   string
+}
+
+// route 路由
+type route struct {
+	auth    bool
+	handler func(r *http.Request, body []byte) any
+}
+
+// Server 抖音开放平台模拟服务
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	seq      int64
+	routes   map[string]route
+	tokens   map[string]struct{}
+	requests []*RecordedRequest
+
+	orders    map[string]*order
+	settles   []*settleOrder
+	refunds   []*refundOrder
+	balances  map[string]*balance
+	withdraws map[string]*withdrawOrder
+	tags      map[int][]*trade.TagGroup
+	albums    map[int64]*album
+	videos    map[string]*video
+	vocJobs   map[string]*vocJob
+	vocVideos map[string]*vocVideo
+	workFlows map[string]*workFlow
+	links     map[string]*linkInfo
+	schemas   map[string]*linkInfo
+}
+
+// New 创建并启动模拟服务
+func New() *Server {
+	s := &Server{
+		routes:    make(map[string]route),
+		tokens:    make(map[string]struct{}),
+		orders:    make(map[string]*order),
+		balances:  make(map[string]*balance),
+		withdraws: make(map[string]*withdrawOrder),
+		tags:      make(map[int][]*trade.TagGroup),
+		albums:    make(map[int64]*album),
+		videos:    make(map[string]*video),
+		vocJobs:   make(map[string]*vocJob),
+		vocVideos: make(map[string]*vocVideo),
+		workFlows: make(map[string]*workFlow),
+		links:     make(map[string]*linkInfo),
+		schemas:   make(map[string]*linkInfo),
+	}
+	s.registerCredential()
+	s.registerPay()
+	s.registerDrama()
+	s.registerVoc()
+	s.registerLink()
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// handle 注册路由，path 统一去掉结尾的 "/"
+func (s *Server) handle(path string, auth bool, handler func(r *http.Request, body []byte) any) {
+	s.routes[strings.TrimSuffix(path, "/")] = route{auth: auth, handler: handler}
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	_ = r.Body.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, &RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Body:   string(body),
+	})
+
+	rt, ok := s.routes[strings.TrimSuffix(r.URL.Path, "/")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	var resp any
+	if rt.auth && !s.authorized(r) {
+		resp = errResponse(ErrNoInvalidAccessToken, "access token is invalid")
+	} else {
+		resp = rt.handler(r, body)
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// authorized 校验 access-token 请求头或 access_token 参数是否为模拟服务下发的 token
+func (s *Server) authorized(r *http.Request) bool {
+	token := r.Header.Get("access-token")
+	if token == "" {
+		token = r.URL.Query().Get("access_token")
+	}
+	_, ok := s.tokens[token]
+	return ok
+}
+
+// Requests 返回收到的全部请求
+func (s *Server) Requests() []*RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*RecordedRequest(nil), s.requests...)
+}
+
+// RequestsTo 返回指定路径收到的请求
+func (s *Server) RequestsTo(path string) []*RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path = strings.TrimSuffix(path, "/")
+	var list []*RecordedRequest
+	for _, req := range s.requests {
+		if strings.TrimSuffix(req.Path, "/") == path {
+			list = append(list, req)
+		}
+	}
+	return list
+}
+
+// nextID 生成递增的 ID，调用方需持有锁
+func (s *Server) nextID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s%d", prefix, 7000000000000000000+s.seq)
+}
+
+// issueToken 下发 access_token，调用方需持有锁
+func (s *Server) issueToken(prefix string) string {
+	token := s.nextID(prefix)
+	s.tokens[token] = struct{}{}
+	return token
+}
+
+// registerCredential 注册凭证相关接口
+func (s *Server) registerCredential() {
+	s.handle("/oauth/client_token", false, func(_ *http.Request, _ []byte) any {
+		return map[string]any{
+			"message": "success",
+			"data": map[string]any{
+				"access_token": s.issueToken("clt."),
+				"expires_in":   tokenExpiresIn,
+				"error_code":   0,
+				"description":  "",
+			},
+		}
+	})
+	s.handle("/api/apps/v2/token", false, func(_ *http.Request, _ []byte) any {
+		return map[string]any{
+			"err_no":   ErrNoSuccess,
+			"err_tips": "success",
+			"data": map[string]any{
+				"access_token": s.issueToken("srv."),
+				"expires_in":   tokenExpiresIn,
+			},
+		}
+	})
+	userToken := func(r *http.Request, _ []byte) any {
+		return map[string]any{
+			"message": "success",
+			"data": map[string]any{
+				"access_token":       s.issueToken("act."),
+				"expires_in":         1296000,
+				"refresh_token":      s.nextID("rft."),
+				"refresh_expires_in": 2592000,
+				"openid":             "fake-open-id",
+				"scope":              r.URL.Query().Get("scope"),
+				"error_code":         0,
+				"description":        "",
+			},
+		}
+	}
+	s.handle("/oauth/access_token", false, userToken)
+	s.handle("/oauth/refresh_token", false, userToken)
+	s.handle("/oauth/renew_refresh_token", false, func(_ *http.Request, _ []byte) any {
+		return map[string]any{
+			"message": "success",
+			"data": map[string]any{
+				"refresh_token": s.nextID("rft."),
+				"expires_in":    2592000,
+				"error_code":    0,
+				"description":   "",
+			},
+		}
+	})
+}
+
+// okResponse 新版开放平台接口成功返回
+func okResponse(data any) map[string]any {
+	resp := map[string]any{
+		"err_no":  ErrNoSuccess,
+		"err_msg": "",
+		"log_id":  "fake-log-id",
+	}
+	if data != nil {
+		resp["data"] = data
+	}
+	return resp
+}
+
+// errResponse 新版开放平台接口错误返回
+func errResponse(errNo int, errMsg string) map[string]any {
+	return map[string]any{
+		"err_no":   errNo,
+		"err_msg":  errMsg,
+		"err_tips": errMsg,
+		"log_id":   "fake-log-id",
+	}
+}
+
+// decode 解析请求体，失败时返回参数错误响应
+func decode(body []byte, v any) map[string]any {
+	if err := json.Unmarshal(body, v); err != nil {
+		return errResponse(ErrNoInvalidParam, "invalid request body: "+err.Error())
+	}
+	return nil
+}
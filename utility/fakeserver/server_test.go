@@ -0,0 +1,250 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package fakeserver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/pay"
+	"github.com/houseme/bytedance/pay/refund"
+	"github.com/houseme/bytedance/pay/settle"
+	"github.com/houseme/bytedance/pay/trade"
+	"github.com/houseme/bytedance/pay/withdraw"
+	"github.com/houseme/bytedance/utility/base"
+	"github.com/houseme/bytedance/utility/cache"
+)
+
+func TestServerPayFlow(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+
+	ctx := context.Background()
+	cfg := config.New(ctx,
+		config.WithBaseURL(srv.URL),
+		config.WithCache(cache.NewMemory()),
+		config.WithClientKey("tt-fake"),
+		config.WithClientSecret("secret"),
+		config.WithSalt("salt"),
+		config.WithToken("token"),
+		config.WithPublicKey("public"),
+		config.WithPrivateKey("private"),
+	)
+	p, err := pay.NewPay(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.AddOrder(&trade.QueryOrderData{OutOrderNo: "out-1", TotalAmount: 1000})
+	order, err := p.Trade().QueryTrade(ctx, &trade.QueryOrderRequest{OutOrderNo: "out-1"})
+	if err != nil || order.ErrNo != ErrNoSuccess || order.Data.TotalAmount != 1000 {
+		t.Fatalf("QueryTrade() = %+v, %v", order, err)
+	}
+
+	_, err = p.Settle().Apply(ctx, &settle.ApplySettleRequest{
+		OutOrderNo:  "out-1",
+		OutSettleNo: "stl-0",
+		SettleDesc:  "分账",
+		Receivers:   []*settle.OtherSettleParam{{MerchantUid: "m-2", Amount: 1001}},
+	})
+	if !errors.Is(err, settle.ErrSettleAmountExceeded) {
+		t.Fatalf("Settle.Apply() over order amount err = %v, want ErrSettleAmountExceeded", err)
+	}
+
+	settleReq := &settle.ApplySettleRequest{
+		OutOrderNo:  "out-1",
+		OutSettleNo: "stl-1",
+		SettleDesc:  "分账",
+		Receivers:   []*settle.OtherSettleParam{{MerchantUid: "m-2", Amount: 300}},
+	}
+	applied, err := p.Settle().Apply(ctx, settleReq)
+	if err != nil || applied.ErrNo != ErrNoSuccess {
+		t.Fatalf("Settle.Apply() = %+v, %v", applied, err)
+	}
+	if settleReq.SettleParams != "" {
+		t.Fatalf("Settle.Apply() mutated request SettleParams = %q", settleReq.SettleParams)
+	}
+	retried, err := p.Settle().Apply(ctx, settleReq)
+	if err != nil || retried.ErrNo != ErrNoSuccess || retried.Data.SettleID != applied.Data.SettleID {
+		t.Fatalf("Settle.Apply() retry = %+v, %v, want settle %s", retried, err, applied.Data.SettleID)
+	}
+	for _, want := range []string{settle.StateProcessing, settle.StateSuccess} {
+		got, err := p.Settle().Query(ctx, &settle.QuerySettleRequest{OutSettleNo: "stl-1"})
+		if err != nil || len(got.Data) != 1 || got.Data[0].SettleStatus != want {
+			t.Fatalf("Settle.Query() = %+v, %v, want status %s", got, err, want)
+		}
+	}
+
+	srv.SetBalance("m-1", withdraw.Alipay, withdraw.AccountInfo{WithdrawAbleBalance: 500})
+	resp, err := p.Withdraw().Apply(ctx, &withdraw.MerchantWithdrawRequest{MerchantUID: "m-1", ChannelType: withdraw.Alipay, WithdrawAmount: 800, OutOrderID: "wd-1"})
+	if err != nil || resp.ErrNo != ErrNoInsufficientBalance {
+		t.Fatalf("Withdraw.Apply() over balance = %+v, %v", resp, err)
+	}
+
+	if n := len(srv.RequestsTo("/oauth/client_token")); n != 1 {
+		t.Errorf("client token requested %d times, want 1", n)
+	}
+}
+
+func TestServerRefund(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+
+	ctx := context.Background()
+	cfg := config.New(ctx,
+		config.WithBaseURL(srv.URL),
+		config.WithCache(cache.NewMemory()),
+		config.WithClientKey("tt-fake"),
+		config.WithClientSecret("secret"),
+		config.WithSalt("salt"),
+		config.WithToken("token"),
+		config.WithPublicKey("public"),
+		config.WithPrivateKey("private"),
+	)
+	p, err := pay.NewPay(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.AddOrder(&trade.QueryOrderData{
+		OutOrderNo:     "out-1",
+		TotalAmount:    1000,
+		DiscountAmount: 100,
+		ItemOrderList: []*trade.ItemOrder{
+			{ItemOrderID: "item-1", ItemOrderAmount: 600},
+			{ItemOrderID: "item-2", ItemOrderAmount: 400},
+		},
+	})
+	schema := &trade.Schema{Path: "page/refund/index"}
+
+	created, err := p.Refund().CreateRefund(ctx, &refund.CreateRefundRequest{
+		OutOrderNo:       "out-1",
+		OutRefundNo:      "rfd-1",
+		OrderEntrySchema: schema,
+		ItemOrderDetail:  []*refund.ItemOrderDetail{{ItemOrderID: "item-1", RefundAmount: 500}},
+	})
+	if err != nil || created.ErrNo != ErrNoSuccess || created.Data.RefundID == "" {
+		t.Fatalf("CreateRefund() item = %+v, %v", created, err)
+	}
+
+	_, err = p.Refund().CreateRefund(ctx, &refund.CreateRefundRequest{
+		OutOrderNo:       "out-1",
+		OutRefundNo:      "rfd-2",
+		OrderEntrySchema: schema,
+		ItemOrderDetail:  []*refund.ItemOrderDetail{{ItemOrderID: "item-1", RefundAmount: 200}},
+	})
+	if !errors.Is(err, base.ErrRefundAmountExceeded) {
+		t.Fatalf("CreateRefund() over item amount err = %v, want ErrRefundAmountExceeded", err)
+	}
+
+	// 整单退款剩余 item-1 100 + item-2 400，合计 500 超过剩余实付 400
+	req := &refund.CreateRefundRequest{OutOrderNo: "out-1", OutRefundNo: "rfd-3", OrderEntrySchema: schema}
+	if _, err = p.Refund().CreateRefund(ctx, req); !errors.Is(err, base.ErrRefundAmountExceeded) {
+		t.Fatalf("CreateRefund() whole order err = %v, want ErrRefundAmountExceeded", err)
+	}
+	if req.ItemOrderDetail != nil || req.RefundTotalAmount != 0 {
+		t.Fatalf("CreateRefund() must not modify req: %+v", req)
+	}
+	for _, tt := range []struct {
+		req  *refund.CreateRefundRequest
+		want error
+	}{
+		{req: &refund.CreateRefundRequest{ItemOrderDetail: []*refund.ItemOrderDetail{{ItemOrderID: "item-9", RefundAmount: 1}}}, want: base.ErrItemOrderNotFound},
+		{req: &refund.CreateRefundRequest{ItemOrderDetail: []*refund.ItemOrderDetail{{ItemOrderID: "item-2", RefundAmount: 0}}}, want: base.ErrInvalidAmount},
+		{req: &refund.CreateRefundRequest{ItemOrderDetail: []*refund.ItemOrderDetail{{ItemOrderID: "item-2", RefundAmount: 100}}, RefundTotalAmount: 200}, want: base.ErrRefundAmountMismatch},
+	} {
+		tt.req.OutOrderNo, tt.req.OutRefundNo, tt.req.OrderEntrySchema = "out-1", "rfd-x", schema
+		if _, err = p.Refund().CreateRefund(ctx, tt.req); !errors.Is(err, tt.want) {
+			t.Errorf("CreateRefund(%+v) err = %v, want %v", tt.req, err, tt.want)
+		}
+	}
+
+	got, err := p.Refund().QueryRefund(ctx, &refund.QueryRefundRequest{OutRefundNo: "rfd-1"})
+	if err != nil || len(got.Data.RefundList) != 1 || got.Data.RefundList[0].RefundTotalAmount != 500 {
+		t.Fatalf("QueryRefund() = %+v, %v", got, err)
+	}
+}
+
+func TestServerTradeTags(t *testing.T) {
+	srv := New()
+	defer srv.Close()
+
+	ctx := context.Background()
+	cfg := config.New(ctx,
+		config.WithBaseURL(srv.URL),
+		config.WithCache(cache.NewMemory()),
+		config.WithClientKey("tt-fake"),
+		config.WithClientSecret("secret"),
+		config.WithSalt("salt"),
+		config.WithToken("token"),
+		config.WithPublicKey("public"),
+		config.WithPrivateKey("private"),
+	)
+	p, err := pay.NewPay(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &trade.CreateOrderRequest{
+		OutOrderNo:       "out-1",
+		TotalAmount:      100,
+		OrderEntrySchema: &trade.Schema{Path: "pages/order"},
+		SkuList: []*trade.SkuItem{{
+			SkuID:      "sku-1",
+			Price:      100,
+			Quantity:   1,
+			Type:       trade.GoodsTypeContent,
+			TagGroupID: trade.VirtualServiceTagID,
+		}},
+	}
+	if _, err = p.Trade().CreateTrade(ctx, req); !errors.Is(err, trade.ErrSkuTagMismatch) {
+		t.Fatalf("CreateTrade() err = %v, want ErrSkuTagMismatch", err)
+	}
+	if n := len(srv.RequestsTo("/api/trade_basic/v1/developer/tag_query")); n != 0 {
+		t.Fatalf("CreateTrade() requested tag_query %d times, want 0", n)
+	}
+	// 未知标签组按平台目录校验，目录被缓存
+	req.SkuList[0].TagGroupID = "tag_group_custom"
+	for i := 0; i < 2; i++ {
+		if err = p.Trade().ValidateSkuTags(ctx, req.SkuList); !errors.Is(err, trade.ErrSkuTagMismatch) {
+			t.Fatalf("ValidateSkuTags() err = %v, want ErrSkuTagMismatch", err)
+		}
+	}
+	if n := len(srv.RequestsTo("/api/trade_basic/v1/developer/tag_query")); n != 1 {
+		t.Errorf("tag_query requested %d times, want 1 (cached)", n)
+	}
+
+	// 空列表不缓存，平台恢复后立即生效
+	srv.SetTagGroups(trade.GoodsTypeRecharge, nil)
+	if groups, err := p.Trade().TagGroups(ctx, trade.GoodsTypeRecharge); err != nil || len(groups) != 0 {
+		t.Fatalf("TagGroups() empty = %+v, %v", groups, err)
+	}
+	srv.SetTagGroups(trade.GoodsTypeRecharge, []*trade.TagGroup{{TagGroupID: trade.VirtualRechargeTagID}})
+	if groups, err := p.Trade().TagGroups(ctx, trade.GoodsTypeRecharge); err != nil || len(groups) != 1 {
+		t.Fatalf("TagGroups() after recovery = %+v, %v", groups, err)
+	}
+
+	groups, err := p.Trade().TagGroups(ctx, trade.GoodsTypeContent)
+	if err != nil || len(groups) != 1 || groups[0].TagGroupID != trade.ContentRechargeTagID {
+		t.Errorf("TagGroups() = %+v, %v", groups, err)
+	}
+}
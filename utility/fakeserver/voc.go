@@ -0,0 +1,222 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package fakeserver
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/houseme/bytedance/minidrama/voc"
+)
+
+const (
+	// vocStateInitial 上传任务初始状态
+	vocStateInitial = "initial"
+	// vocStateSuccess 上传任务成功状态
+	vocStateSuccess = "success"
+
+	// runStatusRunning 转码中
+	runStatusRunning = 2
+	// runStatusSuccess 转码成功
+	runStatusSuccess = 10
+)
+
+// vocJob URL 上传任务，首次查询返回 initial，之后变为 success 并生成视频
+type vocJob struct {
+	jobID     string
+	sourceURL string
+	fileName  string
+	state     string
+	vid       string
+}
+
+// vocVideo 抖音云视频
+type vocVideo struct {
+	vid        string
+	name       string
+	sourceURL  string
+	uploadTime int
+}
+
+// workFlow 转码任务，首次查询返回转码中，之后变为转码成功
+type workFlow struct {
+	runID  string
+	vid    string
+	status int
+}
+
+// registerVoc 注册抖音云视频点播接口
+func (s *Server) registerVoc() {
+	s.handle("/api/dyc_voc/upload_video_by_urls", true, s.vocUpload)
+	s.handle("/api/dyc_voc/get_upload_job_info", true, s.vocJobInfo)
+	s.handle("/api/dyc_voc/get_video_list", true, s.vocVideoList)
+	s.handle("/api/dyc_voc/delete_video", true, s.vocDelete)
+	s.handle("/api/dyc_voc/get_video_by_vid", true, s.vocVideoByVID)
+	s.handle("/api/dyc_voc/start_work_flow", true, s.vocStartWorkFlow)
+	s.handle("/api/dyc_voc/get_work_flow_exection", true, s.vocWorkFlow)
+}
+
+func (s *Server) vocUpload(_ *http.Request, body []byte) any {
+	var req voc.UploadByURLRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	if len(req.UrlSets) == 0 {
+		return errResponse(ErrNoInvalidParam, "url_sets is empty")
+	}
+	jobs := make([]*voc.URLJob, 0, len(req.UrlSets))
+	for _, set := range req.UrlSets {
+		j := &vocJob{jobID: s.nextID("job"), sourceURL: set.SourceURL, fileName: set.FileName, state: vocStateInitial}
+		s.vocJobs[j.jobID] = j
+		jobs = append(jobs, &voc.URLJob{JobID: j.jobID, SourceURL: j.sourceURL})
+	}
+	return okResponse(&voc.UploadByURLRData{UrlJobs: jobs})
+}
+
+func (s *Server) vocJobInfo(_ *http.Request, body []byte) any {
+	var req voc.QueryUploadByURLRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	data := &voc.QueryUploadByURLData{NotExistJobIds: make([]string, 0), VideoInfos: make([]*voc.VideoInfo, 0)}
+	for _, id := range req.JobIds {
+		j, ok := s.vocJobs[id]
+		if !ok {
+			data.NotExistJobIds = append(data.NotExistJobIds, id)
+			continue
+		}
+		info := &voc.VideoInfo{State: j.state, ViD: j.vid, JobID: j.jobID, RequestID: s.nextID("req"), SourceURL: j.sourceURL}
+		if j.state == vocStateSuccess {
+			info.SourceInfo = &voc.SourceInfo{FileType: "video", Format: "mp4", Size: 1024, Width: 720, Height: 1280, Duration: 60}
+		}
+		data.VideoInfos = append(data.VideoInfos, info)
+		if j.state == vocStateInitial {
+			j.state, j.vid = vocStateSuccess, s.nextID("v0")
+			s.vocVideos[j.vid] = &vocVideo{vid: j.vid, name: j.fileName, sourceURL: j.sourceURL, uploadTime: int(time.Now().Unix())}
+		}
+	}
+	return okResponse(data)
+}
+
+func (s *Server) vocVideoList(_ *http.Request, body []byte) any {
+	var req voc.QueryListRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	vids := make([]string, 0, len(s.vocVideos))
+	for vid, v := range s.vocVideos {
+		if req.VideoName == "" || v.name == req.VideoName {
+			vids = append(vids, vid)
+		}
+	}
+	sort.Strings(vids)
+	size := req.PageSize
+	if size <= 0 {
+		size = 10
+	}
+	number := req.PageNumber
+	if number <= 0 {
+		number = 1
+	}
+	start, end := (number-1)*size, number*size
+	if start > len(vids) {
+		start = len(vids)
+	}
+	if end > len(vids) {
+		end = len(vids)
+	}
+	list := make([]*voc.QueryDataItem, 0, end-start)
+	for _, vid := range vids[start:end] {
+		v := s.vocVideos[vid]
+		list = append(list, &voc.QueryDataItem{BusinessStatus: 1, Size: 1024, UploadTime: v.uploadTime, Vid: v.vid, VideoName: v.name})
+	}
+	resp := okResponse(list)
+	resp["total_num"] = len(vids)
+	return resp
+}
+
+func (s *Server) vocDelete(_ *http.Request, body []byte) any {
+	var req voc.DeleteVideoRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	if _, ok := s.vocVideos[req.Vid]; !ok {
+		return errResponse(ErrNoNotFound, "vid not exist")
+	}
+	delete(s.vocVideos, req.Vid)
+	return okResponse(nil)
+}
+
+func (s *Server) vocVideoByVID(_ *http.Request, body []byte) any {
+	var req voc.QueryVideoURLRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	if _, ok := s.vocVideos[req.Vid]; !ok {
+		return errResponse(ErrNoNotFound, "vid not exist")
+	}
+	return okResponse([]*voc.QueryVideoURLData{{
+		Format:        "mp4",
+		MainPlayUrl:   s.URL + "/voc/" + req.Vid + ".mp4",
+		BackUpPlayUrl: s.URL + "/voc/backup/" + req.Vid + ".mp4",
+		Size:          1024,
+		Bitrate:       1000,
+		Codec:         "h264",
+		Definition:    "720p",
+		MainUrlExpire: "3600",
+		BackUrlExpire: "3600",
+	}})
+}
+
+func (s *Server) vocStartWorkFlow(_ *http.Request, body []byte) any {
+	var req voc.StartWorkFlowRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	if _, ok := s.vocVideos[req.Vid]; !ok {
+		return errResponse(ErrNoNotFound, "vid not exist")
+	}
+	w := &workFlow{runID: s.nextID("run"), vid: req.Vid, status: runStatusRunning}
+	s.workFlows[w.runID] = w
+	resp := okResponse(nil)
+	resp["run_id"] = w.runID
+	return resp
+}
+
+func (s *Server) vocWorkFlow(_ *http.Request, body []byte) any {
+	var req voc.QueryWorkFlowRequest
+	if resp := decode(body, &req); resp != nil {
+		return resp
+	}
+	w, ok := s.workFlows[req.RunID]
+	if !ok {
+		return errResponse(ErrNoNotFound, "run_id not exist")
+	}
+	resp := okResponse(nil)
+	resp["run_status"] = w.status
+	if w.status == runStatusSuccess {
+		resp["run_status_msg"] = "success"
+	} else {
+		resp["run_status_msg"] = "running"
+		w.status = runStatusSuccess
+	}
+	return resp
+}
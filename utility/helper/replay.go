@@ -0,0 +1,114 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/houseme/bytedance/utility/base"
+	"github.com/houseme/bytedance/utility/cache"
+)
+
+// nonceMu 串行化同一进程内所有 ReplayGuard 的 nonce 校验与记录，
+// 按当前配置临时创建的 ReplayGuard 之间同样互斥
+var nonceMu sync.Mutex
+
+// ReplayGuard 回调防重放校验：时间戳需在允许的偏差内，nonce 在保留时长内不可重复。
+// 同一进程内 nonce 的校验与记录是原子的；cache.Cache 没有原子的 set-if-absent，多实例之间仍可能各通过一次
+type ReplayGuard struct {
+	cache     cache.Cache
+	keyPrefix string
+	skew      time.Duration
+	nonceTTL  time.Duration
+	now       func() time.Time
+}
+
+// ReplayClaim 通过校验、处理中的回调，业务处理失败时调用 Finish 释放 nonce 以便平台重试
+type ReplayClaim struct {
+	guard *ReplayGuard
+	nonce string
+}
+
+// NewReplayGuard 实例化，skew 为 0 时不校验时间戳，nonceTTL 为 0 或 c 为 nil 时不校验 nonce
+func NewReplayGuard(c cache.Cache, keyPrefix string, skew, nonceTTL time.Duration) *ReplayGuard {
+	return &ReplayGuard{
+		cache:     c,
+		keyPrefix: keyPrefix,
+		skew:      skew,
+		nonceTTL:  nonceTTL,
+		now:       time.Now,
+	}
+}
+
+// SetClock 设置时间来源
+func (g *ReplayGuard) SetClock(now func() time.Time) *ReplayGuard {
+	g.now = now
+	return g
+}
+
+// Check 校验秒级时间戳与 nonce，需在验签通过后调用，避免伪造请求占用 nonce。
+// 通过后 nonce 立即写入缓存并保留 nonceTTL，不调用 Finish 时同一回调在保留期内也无法重放；
+// 业务处理失败时调用 claim.Finish 删除 nonce，平台可用同一 nonce 重试
+func (g *ReplayGuard) Check(ctx context.Context, timestamp, nonce string) (*ReplayClaim, error) {
+	if g.skew > 0 {
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return nil, base.ErrCallbackTimestampExpired
+		}
+		if diff := g.now().Sub(time.Unix(ts, 0)); diff > g.skew || diff < -g.skew {
+			return nil, base.ErrCallbackTimestampExpired
+		}
+	}
+	if g.nonceTTL <= 0 || g.cache == nil {
+		return nil, nil
+	}
+
+	nonceMu.Lock()
+	defer nonceMu.Unlock()
+	if g.cache.IsExist(ctx, g.key(nonce)) {
+		return nil, base.ErrCallbackNonceReused
+	}
+	if err := g.cache.Set(ctx, g.key(nonce), timestamp, g.nonceTTL); err != nil {
+		return nil, fmt.Errorf("replay guard: store nonce: %w", err)
+	}
+	return &ReplayClaim{guard: g, nonce: nonce}, nil
+}
+
+// key nonce 的缓存 key
+func (g *ReplayGuard) key(nonce string) string {
+	return g.keyPrefix + "_callback_nonce_" + nonce
+}
+
+// Finish 结束处理并返回 handleErr：handleErr 为 nil 时保留已记录的 nonce；否则删除 nonce 以便平台重试，删除失败时一并返回。
+// c 为 nil（未校验 nonce）时直接返回 handleErr
+func (c *ReplayClaim) Finish(ctx context.Context, handleErr error) error {
+	if c == nil || handleErr == nil {
+		return handleErr
+	}
+	if err := c.guard.cache.Delete(ctx, c.guard.key(c.nonce)); err != nil {
+		return errors.Join(handleErr, fmt.Errorf("replay guard: release nonce: %w", err))
+	}
+	return handleErr
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package helper
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/houseme/bytedance/utility/base"
+	"github.com/houseme/bytedance/utility/cache"
+)
+
+func TestReplayGuard(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		now   = time.Unix(1700000000, 0)
+		guard = NewReplayGuard(cache.NewMemory(), "test", 5*time.Minute, time.Hour).SetClock(func() time.Time { return now })
+		ts    = func(d time.Duration) string { return strconv.FormatInt(now.Add(d).Unix(), 10) }
+	)
+	claim, err := guard.Check(ctx, ts(-time.Minute), "n1")
+	if err != nil {
+		t.Fatalf("fresh Check() error = %v", err)
+	}
+	if err = claim.Finish(ctx, nil); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		timestamp string
+		nonce     string
+		want      error
+	}{
+		{name: "reused nonce", timestamp: ts(0), nonce: "n1", want: base.ErrCallbackNonceReused},
+		{name: "expired", timestamp: ts(-10 * time.Minute), nonce: "n2", want: base.ErrCallbackTimestampExpired},
+		{name: "future", timestamp: ts(10 * time.Minute), nonce: "n3", want: base.ErrCallbackTimestampExpired},
+		{name: "invalid", timestamp: "abc", nonce: "n4", want: base.ErrCallbackTimestampExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := guard.Check(ctx, tt.timestamp, tt.nonce); !errors.Is(err, tt.want) {
+				t.Errorf("Check() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// 处理失败后释放，平台用同一 nonce 重试可以通过
+	claim, _ = guard.Check(ctx, ts(0), "n5")
+	if _, err = guard.Check(ctx, ts(0), "n5"); !errors.Is(err, base.ErrCallbackNonceReused) {
+		t.Errorf("unfinished Check() error = %v", err)
+	}
+	if handleErr := errors.New("handler failed"); !errors.Is(claim.Finish(ctx, handleErr), handleErr) {
+		t.Error("Finish() should return the handler error")
+	}
+	if _, err = guard.Check(ctx, ts(0), "n5"); err != nil {
+		t.Errorf("retry after failure Check() error = %v", err)
+	}
+
+	if claim, err = NewReplayGuard(nil, "test", 0, 0).Check(ctx, "0", "n1"); err != nil || claim.Finish(ctx, nil) != nil {
+		t.Errorf("disabled guard Check() error = %v", err)
+	}
+}
+
+func TestReplayGuardWithoutFinish(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		now    = time.Unix(1700000000, 0)
+		memory = cache.NewMemory()
+		guard  = NewReplayGuard(memory, "test", 0, time.Hour).SetClock(func() time.Time { return now })
+	)
+	// 调用方未调用 Finish，nonce 在校验时已记录
+	if _, err := guard.Check(ctx, "1", "n1"); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	now = now.Add(10 * time.Minute)
+	if _, err := guard.Check(ctx, "1", "n1"); !errors.Is(err, base.ErrCallbackNonceReused) {
+		t.Errorf("replay after 10 minutes Check() error = %v, want ErrCallbackNonceReused", err)
+	}
+	// 共用缓存的其他实例
+	if _, err := NewReplayGuard(memory, "test", 0, time.Hour).Check(ctx, "1", "n1"); !errors.Is(err, base.ErrCallbackNonceReused) {
+		t.Errorf("replay on another guard Check() error = %v, want ErrCallbackNonceReused", err)
+	}
+}
+
+func TestReplayGuardConcurrent(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		guard  = NewReplayGuard(cache.NewMemory(), "test", 0, time.Hour)
+		passed atomic.Int32
+		wg     sync.WaitGroup
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := guard.Check(ctx, "1", "same"); err == nil {
+				passed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if passed.Load() != 1 {
+		t.Errorf("%d concurrent Check() passed, want 1", passed.Load())
+	}
+}
+
+// failingCache Set 总是失败
+type failingCache struct{ cache.Cache }
+
+func (failingCache) Set(context.Context, string, interface{}, time.Duration) error {
+	return errors.New("cache down")
+}
+
+func TestReplayGuardStoreError(t *testing.T) {
+	ctx := context.Background()
+	guard := NewReplayGuard(failingCache{cache.NewMemory()}, "test", 0, time.Hour)
+	if _, err := guard.Check(ctx, "1", "n1"); err == nil || errors.Is(err, base.ErrCallbackNonceReused) {
+		t.Errorf("Check() error = %v, want cache error", err)
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"crypto/sha1"
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -118,6 +119,10 @@ func RequestSign(_ context.Context, data interface{}, salt string) string {
 			if k == OtherSettleParams || k == AppID || k == ThirdPartyID || k == Sign {
 				continue
 			}
+			// omitempty 的零值不会出现在请求体中，也不参与签名
+			if v.Field(i).IsZero() && slices.Contains(tagKeyArr[1:], "omitempty") {
+				continue
+			}
 
 			if reflect.ValueOf(v.Field(i).Interface()).Kind() == reflect.Ptr && reflect.ValueOf(v.Field(i).Interface()).IsNil() {
 				continue
@@ -91,6 +91,26 @@ func TestConcatenateSignSource(t *testing.T) {
 			},
 			want: "ac65148dbe5fe6ca969a76aaa66f1c2e",
 		},
+		{
+			name: "TestConcatenateSignSourceOmitEmptyZero",
+			args: args{
+				ctx: context.Background(),
+				// disable_msg 为 0 时不出现在请求体中，签名与不含该字段时一致
+				data: struct {
+					AppID       string `json:"app_id"`
+					OutTradeNo  string `json:"out_trade_no,omitempty"`
+					TotalAmount int    `json:"total_amount,omitempty"`
+					DisableMsg  int    `json:"disable_msg,omitempty"`
+				}{
+					AppID:       "appid12345",
+					OutTradeNo:  "out_trade_no",
+					TotalAmount: 100,
+				},
+				salt:   "test",
+				logger: logger.NewDefaultLogger(),
+			},
+			want: "2001ff1c8ffa0f6b4bfb592133a84294",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
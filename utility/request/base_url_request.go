@@ -0,0 +1,98 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package request
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// BaseURLRequest 将所有请求的 scheme 与 host 替换为 baseURL 后交给 next 执行，
+// 路径与 query 参数保持不变，常用于将 SDK 指向测试环境或本地的模拟服务。
+type BaseURLRequest struct {
+	baseURL *url.URL
+	next    Request
+}
+
+// NewBaseURLRequest 实例化，baseURL 形如 http://127.0.0.1:8080，可以携带路径前缀
+func NewBaseURLRequest(baseURL string, next Request) (*BaseURLRequest, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("request: base url %q must contain scheme and host", baseURL)
+	}
+	return &BaseURLRequest{baseURL: u, next: next}, nil
+}
+
+// rewrite 替换 URL 的 scheme 与 host
+func (srv *BaseURLRequest) rewrite(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.Scheme = srv.baseURL.Scheme
+	u.Host = srv.baseURL.Host
+	u.Path = srv.baseURL.Path + u.Path
+	return u.String()
+}
+
+// Get HTTP get request
+func (srv *BaseURLRequest) Get(ctx context.Context, url string) ([]byte, error) {
+	return srv.next.Get(ctx, srv.rewrite(url))
+}
+
+// Post HTTP post request
+func (srv *BaseURLRequest) Post(ctx context.Context, url string, data []byte) ([]byte, error) {
+	return srv.next.Post(ctx, srv.rewrite(url), data)
+}
+
+// PostJSON HTTP post JSON request
+func (srv *BaseURLRequest) PostJSON(ctx context.Context, url string, data any) ([]byte, error) {
+	return srv.next.PostJSON(ctx, srv.rewrite(url), data)
+}
+
+// PostJSONWithRespContentType HTTP post JSON request with the response content type
+func (srv *BaseURLRequest) PostJSONWithRespContentType(ctx context.Context, url string, data any) ([]byte, string, error) {
+	return srv.next.PostJSONWithRespContentType(ctx, srv.rewrite(url), data)
+}
+
+// PostFile HTTP post file request
+func (srv *BaseURLRequest) PostFile(ctx context.Context, url string, files []MultipartFormField) ([]byte, error) {
+	return srv.next.PostFile(ctx, srv.rewrite(url), files)
+}
+
+// PostMultipartForm HTTP post multipart form request
+func (srv *BaseURLRequest) PostMultipartForm(ctx context.Context, url string, files []MultipartFormField) ([]byte, error) {
+	return srv.next.PostMultipartForm(ctx, srv.rewrite(url), files)
+}
+
+// PostXML perform the HTTP/POST request with XML body
+func (srv *BaseURLRequest) PostXML(ctx context.Context, url string, data any) ([]byte, error) {
+	return srv.next.PostXML(ctx, srv.rewrite(url), data)
+}
+
+// PostXMLWithTLS perform the HTTP/POST request with XML body and TLS
+func (srv *BaseURLRequest) PostXMLWithTLS(ctx context.Context, url string, data any, ca, key string) ([]byte, error) {
+	return srv.next.PostXMLWithTLS(ctx, srv.rewrite(url), data, ca, key)
+}
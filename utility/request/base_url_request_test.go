@@ -0,0 +1,41 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package request
+
+import (
+	"testing"
+)
+
+func TestNewBaseURLRequest(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		wantErr bool
+	}{
+		{baseURL: "http://127.0.0.1:8080/"},
+		{baseURL: "https://example.com/prefix"},
+		{baseURL: "127.0.0.1:8080", wantErr: true},
+		{baseURL: "/only/path", wantErr: true},
+	}
+	for _, tt := range tests {
+		if _, err := NewBaseURLRequest(tt.baseURL, nil); (err != nil) != tt.wantErr {
+			t.Errorf("NewBaseURLRequest(%q) error = %v, wantErr %v", tt.baseURL, err, tt.wantErr)
+		}
+	}
+}
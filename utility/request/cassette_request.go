@@ -0,0 +1,593 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package request
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CassetteMode 录制或回放模式
+type CassetteMode int
+
+const (
+	// ModeRecord 通过真实请求录制交互并写入 cassette 文件
+	ModeRecord CassetteMode = iota
+	// ModeReplay 从 cassette 文件回放交互，不发起任何网络请求
+	ModeReplay
+)
+
+// Matcher 回放时请求的匹配维度，可以按位组合
+type Matcher uint
+
+const (
+	// MatchMethod 匹配 HTTP 方法
+	MatchMethod Matcher = 1 << iota
+	// MatchURL 匹配完整 URL（包括 query 参数）
+	MatchURL
+	// MatchBody 匹配请求体，JSON 请求体按语义比较，忽略字段顺序
+	MatchBody
+
+	// MatchAll 匹配方法、URL 和请求体
+	MatchAll = MatchMethod | MatchURL | MatchBody
+)
+
+// ScrubbedValue 脱敏后的占位值
+const ScrubbedValue = "[SCRUBBED]"
+
+// queryParamPattern 匹配文本中 URL query 形式的参数：分隔符、参数名、参数值
+var queryParamPattern = regexp.MustCompile(`([?&])([^?&=\s"]+)=([^&\s"]*)`)
+
+// defaultScrubKeys 默认脱敏的字段，同时作用于 URL query 参数和 JSON 请求/响应体
+var defaultScrubKeys = []string{
+	"access_token",
+	"client_secret",
+	"secret",
+	"refresh_token",
+	"code",
+	"anonymous_code",
+	"session_key",
+}
+
+// Interaction 一次请求与响应的记录
+type Interaction struct {
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	Body        string `json:"body,omitempty"`
+	Response    string `json:"response,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Cassette 录制的交互列表
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// CassetteOption cassette request option
+type CassetteOption func(*CassetteRequest)
+
+// WithMatcher 设置回放时的匹配维度，默认 MatchAll
+func WithMatcher(matcher Matcher) CassetteOption {
+	return func(c *CassetteRequest) {
+		c.matcher = matcher
+	}
+}
+
+// WithScrubKeys 追加需要脱敏的字段名
+func WithScrubKeys(keys ...string) CassetteOption {
+	return func(c *CassetteRequest) {
+		for _, key := range keys {
+			c.scrubKeys[strings.ToLower(key)] = struct{}{}
+		}
+	}
+}
+
+// CassetteRequest 录制/回放 HTTP 交互的 Request 实现，用于编写可重复执行的集成测试
+//
+// 录制模式下请求会透传给 next，请求与响应在脱敏后保存，调用 Save 写入文件；
+// 回放模式下从文件读取交互，按 Matcher 查找匹配项，未匹配的请求返回带差异说明的错误。
+type CassetteRequest struct {
+	path      string
+	mode      CassetteMode
+	next      Request
+	matcher   Matcher
+	scrubKeys map[string]struct{}
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// NewRecordRequest 创建录制模式的 CassetteRequest，next 为真实发起请求的 Request
+func NewRecordRequest(path string, next Request, opts ...CassetteOption) *CassetteRequest {
+	c := newCassetteRequest(path, ModeRecord, opts...)
+	c.next = next
+	return c
+}
+
+// NewReplayRequest 创建回放模式的 CassetteRequest，从 path 加载 cassette 文件
+func NewReplayRequest(path string, opts ...CassetteOption) (*CassetteRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load cassette %s error: %w", path, err)
+	}
+	c := newCassetteRequest(path, ModeReplay, opts...)
+	if err = json.Unmarshal(data, c.cassette); err != nil {
+		return nil, fmt.Errorf("decode cassette %s error: %w", path, err)
+	}
+	c.used = make([]bool, len(c.cassette.Interactions))
+	return c, nil
+}
+
+func newCassetteRequest(path string, mode CassetteMode, opts ...CassetteOption) *CassetteRequest {
+	c := &CassetteRequest{
+		path:      path,
+		mode:      mode,
+		matcher:   MatchAll,
+		scrubKeys: make(map[string]struct{}, len(defaultScrubKeys)),
+		cassette:  &Cassette{Interactions: make([]*Interaction, 0)},
+	}
+	for _, key := range defaultScrubKeys {
+		c.scrubKeys[key] = struct{}{}
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Mode 返回当前模式
+func (c *CassetteRequest) Mode() CassetteMode {
+	return c.mode
+}
+
+// Interactions 返回已录制或已加载的交互
+func (c *CassetteRequest) Interactions() []*Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*Interaction(nil), c.cassette.Interactions...)
+}
+
+// Unused 返回回放模式下尚未被使用的交互，便于测试断言所有录制的请求都被发起
+func (c *CassetteRequest) Unused() []*Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var unused []*Interaction
+	for i, used := range c.used {
+		if !used {
+			unused = append(unused, c.cassette.Interactions[i])
+		}
+	}
+	return unused
+}
+
+// Save 将录制的交互写入 cassette 文件，仅录制模式可用
+func (c *CassetteRequest) Save() error {
+	if c.mode != ModeRecord {
+		return errors.New("cassette: save is only available in record mode")
+	}
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c.cassette, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(c.path); dir != "" {
+		if err = os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(c.path, data, 0o644)
+}
+
+// Get HTTP get request
+func (c *CassetteRequest) Get(ctx context.Context, url string) ([]byte, error) {
+	resp, _, err := c.do(http.MethodGet, url, "", func() ([]byte, string, error) {
+		res, err := c.next.Get(ctx, url)
+		return res, "", err
+	})
+	return resp, err
+}
+
+// Post HTTP post request
+func (c *CassetteRequest) Post(ctx context.Context, url string, data []byte) ([]byte, error) {
+	resp, _, err := c.do(http.MethodPost, url, string(data), func() ([]byte, string, error) {
+		res, err := c.next.Post(ctx, url, data)
+		return res, "", err
+	})
+	return resp, err
+}
+
+// PostJSON HTTP post JSON request
+func (c *CassetteRequest) PostJSON(ctx context.Context, url string, data any) ([]byte, error) {
+	body, err := encodeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	resp, _, err := c.do(http.MethodPost, url, body, func() ([]byte, string, error) {
+		res, err := c.next.PostJSON(ctx, url, data)
+		return res, "", err
+	})
+	return resp, err
+}
+
+// PostJSONWithRespContentType HTTP post JSON request with the response content type
+func (c *CassetteRequest) PostJSONWithRespContentType(ctx context.Context, url string, data any) ([]byte, string, error) {
+	body, err := encodeJSON(data)
+	if err != nil {
+		return nil, "", err
+	}
+	return c.do(http.MethodPost, url, body, func() ([]byte, string, error) {
+		return c.next.PostJSONWithRespContentType(ctx, url, data)
+	})
+}
+
+// PostFile HTTP post file request
+func (c *CassetteRequest) PostFile(ctx context.Context, url string, files []MultipartFormField) ([]byte, error) {
+	return c.PostMultipartForm(ctx, url, files)
+}
+
+// PostMultipartForm HTTP post multipart form request
+func (c *CassetteRequest) PostMultipartForm(ctx context.Context, url string, files []MultipartFormField) ([]byte, error) {
+	body, err := encodeMultipart(files)
+	if err != nil {
+		return nil, err
+	}
+	resp, _, err := c.do(http.MethodPost, url, body, func() ([]byte, string, error) {
+		res, err := c.next.PostMultipartForm(ctx, url, files)
+		return res, "", err
+	})
+	return resp, err
+}
+
+// PostXML perform the HTTP/POST request with XML body
+func (c *CassetteRequest) PostXML(ctx context.Context, url string, data any) ([]byte, error) {
+	body, err := xml.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	resp, _, err := c.do(http.MethodPost, url, string(body), func() ([]byte, string, error) {
+		res, err := c.next.PostXML(ctx, url, data)
+		return res, "", err
+	})
+	return resp, err
+}
+
+// PostXMLWithTLS perform the HTTP/POST request with XML body and TLS
+func (c *CassetteRequest) PostXMLWithTLS(ctx context.Context, url string, data any, ca, key string) ([]byte, error) {
+	body, err := xml.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	resp, _, err := c.do(http.MethodPost, url, string(body), func() ([]byte, string, error) {
+		res, err := c.next.PostXMLWithTLS(ctx, url, data, ca, key)
+		return res, "", err
+	})
+	return resp, err
+}
+
+// do 录制模式下执行 call 并保存结果，回放模式下查找匹配的交互
+func (c *CassetteRequest) do(method, rawURL, body string, call func() ([]byte, string, error)) ([]byte, string, error) {
+	actual := &Interaction{
+		Method: method,
+		URL:    c.scrubURL(rawURL),
+		Body:   c.scrubBody(body),
+	}
+
+	if c.mode == ModeReplay {
+		matched, err := c.match(actual)
+		if err != nil {
+			return nil, "", err
+		}
+		if matched.Error != "" {
+			return nil, "", errors.New(matched.Error)
+		}
+		return []byte(matched.Response), matched.ContentType, nil
+	}
+
+	if c.next == nil {
+		return nil, "", errors.New("cassette: next request is nil in record mode")
+	}
+	resp, contentType, err := call()
+	actual.Response = c.scrubBody(string(resp))
+	actual.ContentType = contentType
+	if err != nil {
+		actual.Error = c.scrubError(err.Error())
+	}
+	c.mu.Lock()
+	c.cassette.Interactions = append(c.cassette.Interactions, actual)
+	c.mu.Unlock()
+	return resp, contentType, err
+}
+
+// match 查找第一个未被使用的匹配交互，全部被使用时复用最后一个匹配项
+func (c *CassetteRequest) match(actual *Interaction) (*Interaction, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reused := -1
+	for i, recorded := range c.cassette.Interactions {
+		if !c.matches(recorded, actual) {
+			continue
+		}
+		if !c.used[i] {
+			c.used[i] = true
+			return recorded, nil
+		}
+		reused = i
+	}
+	if reused >= 0 {
+		return c.cassette.Interactions[reused], nil
+	}
+	return nil, c.mismatchError(actual)
+}
+
+func (c *CassetteRequest) matches(recorded, actual *Interaction) bool {
+	if c.matcher&MatchMethod != 0 && recorded.Method != actual.Method {
+		return false
+	}
+	if c.matcher&MatchURL != 0 && recorded.URL != actual.URL {
+		return false
+	}
+	if c.matcher&MatchBody != 0 && normalizeBody(recorded.Body) != normalizeBody(actual.Body) {
+		return false
+	}
+	return true
+}
+
+// mismatchError 生成未匹配错误，附带与最接近的录制交互之间的差异
+func (c *CassetteRequest) mismatchError(actual *Interaction) error {
+	var (
+		closest *Interaction
+		best    = -1
+	)
+	for _, recorded := range c.cassette.Interactions {
+		score := 0
+		if recorded.Method == actual.Method {
+			score++
+		}
+		if urlPath(recorded.URL) == urlPath(actual.URL) {
+			score += 2
+		}
+		if recorded.URL == actual.URL {
+			score++
+		}
+		if score > best {
+			best, closest = score, recorded
+		}
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "cassette %s: no interaction matches %s %s", c.path, actual.Method, actual.URL)
+	if closest == nil {
+		buf.WriteString(" (cassette is empty)")
+		return errors.New(buf.String())
+	}
+	fmt.Fprintf(&buf, "\nclosest recorded interaction: %s %s\n", closest.Method, closest.URL)
+	buf.WriteString(lineDiff(
+		describe(closest.Method, closest.URL, closest.Body),
+		describe(actual.Method, actual.URL, actual.Body),
+	))
+	return errors.New(buf.String())
+}
+
+// scrubURL 对 URL query 中的敏感参数脱敏
+func (c *CassetteRequest) scrubURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return rawURL
+	}
+	query := u.Query()
+	for key := range query {
+		if _, ok := c.scrubKeys[strings.ToLower(key)]; ok {
+			query.Set(key, ScrubbedValue)
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// scrubError 对错误信息中 query 形式的敏感参数脱敏，DefaultRequest 返回的 *url.Error 带有完整 URL
+func (c *CassetteRequest) scrubError(msg string) string {
+	return queryParamPattern.ReplaceAllStringFunc(msg, func(param string) string {
+		m := queryParamPattern.FindStringSubmatch(param)
+		if _, ok := c.scrubKeys[strings.ToLower(m[2])]; ok {
+			return m[1] + m[2] + "=" + ScrubbedValue
+		}
+		return param
+	})
+}
+
+// scrubBody 对 JSON 请求/响应体中的敏感字段脱敏，非 JSON 内容原样返回
+func (c *CassetteRequest) scrubBody(body string) string {
+	var value any
+	if strings.TrimSpace(body) == "" || json.Unmarshal([]byte(body), &value) != nil {
+		return body
+	}
+	out, err := encodeJSON(c.scrubValue(value))
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+func (c *CassetteRequest) scrubValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, val := range v {
+			if _, ok := c.scrubKeys[strings.ToLower(key)]; ok {
+				// 只对非空字符串脱敏，避免改变数值等字段的类型
+				if s, isStr := val.(string); isStr && s != "" {
+					v[key] = ScrubbedValue
+				}
+				continue
+			}
+			v[key] = c.scrubValue(val)
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = c.scrubValue(v[i])
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// encodeJSON 与 DefaultRequest 保持一致的 JSON 编码方式
+func encodeJSON(data any) (string, error) {
+	var (
+		buf = new(bytes.Buffer)
+		enc = json.NewEncoder(buf)
+	)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(data); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// encodeMultipart 将表单字段编码为可比较的 JSON，文件只记录字段名与文件名
+func encodeMultipart(files []MultipartFormField) (string, error) {
+	type field struct {
+		FieldName string `json:"field_name"`
+		FileName  string `json:"file_name,omitempty"`
+		Value     string `json:"value,omitempty"`
+	}
+	fields := make([]field, 0, len(files))
+	for _, f := range files {
+		item := field{FieldName: f.FieldName}
+		if f.IsFile {
+			item.FileName = filepath.Base(f.FileName)
+		} else {
+			item.Value = string(f.Value)
+		}
+		fields = append(fields, item)
+	}
+	return encodeJSON(fields)
+}
+
+// normalizeBody 将 JSON 请求体规范化，使字段顺序不影响匹配
+func normalizeBody(body string) string {
+	var value any
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return strings.TrimSpace(body)
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return strings.TrimSpace(body)
+	}
+	return string(out)
+}
+
+func urlPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Host + u.Path
+}
+
+// describe 将请求展开为逐行文本，用于生成差异
+func describe(method, rawURL, body string) []string {
+	lines := []string{"method: " + method}
+	if u, err := url.Parse(rawURL); err == nil {
+		lines = append(lines, "url: "+u.Scheme+"://"+u.Host+u.Path)
+		query := u.Query()
+		keys := make([]string, 0, len(query))
+		for key := range query {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			lines = append(lines, "query "+key+"="+strings.Join(query[key], ","))
+		}
+	} else {
+		lines = append(lines, "url: "+rawURL)
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(body), &value); err == nil {
+		pretty, _ := json.MarshalIndent(value, "", "  ")
+		body = string(pretty)
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, "body "+line)
+		}
+	}
+	return lines
+}
+
+// lineDiff 基于最长公共子序列输出逐行差异，"-" 为录制内容，"+" 为实际请求
+func lineDiff(recorded, actual []string) string {
+	n, m := len(recorded), len(actual)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if recorded[i] == actual[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var buf strings.Builder
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case recorded[i] == actual[j]:
+			buf.WriteString("  " + recorded[i] + "\n")
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			buf.WriteString("- " + recorded[i] + "\n")
+			i++
+		default:
+			buf.WriteString("+ " + actual[j] + "\n")
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		buf.WriteString("- " + recorded[i] + "\n")
+	}
+	for ; j < m; j++ {
+		buf.WriteString("+ " + actual[j] + "\n")
+	}
+	return buf.String()
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package request
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassetteRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"err_no":0,"access_token":"live-token","echo":` + strings.TrimSpace(string(body)) + `}`))
+	}))
+	defer server.Close()
+
+	var (
+		ctx  = context.Background()
+		path = filepath.Join(t.TempDir(), "trade.json")
+		url  = server.URL + "/api/trade_basic/v1/developer/order_query/?access_token=live-token"
+	)
+
+	recorder := NewRecordRequest(path, NewDefaultRequest("accessTokenKey"))
+	if _, err := recorder.PostJSON(ctx, url, map[string]any{"out_order_no": "no-1", "secret": "s3cret"}); err != nil {
+		t.Fatalf("record PostJSON() error = %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"live-token", "s3cret"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains unscrubbed secret %q", secret)
+		}
+	}
+
+	player, err := NewReplayRequest(path)
+	if err != nil {
+		t.Fatalf("NewReplayRequest() error = %v", err)
+	}
+	server.Close()
+
+	resp, err := player.PostJSON(ctx, url, map[string]any{"secret": "other", "out_order_no": "no-1"})
+	if err != nil {
+		t.Fatalf("replay PostJSON() error = %v", err)
+	}
+	if !strings.Contains(string(resp), `"out_order_no":"no-1"`) {
+		t.Errorf("replay PostJSON() = %s, want recorded echo", resp)
+	}
+	if unused := player.Unused(); len(unused) != 0 {
+		t.Errorf("Unused() = %d interactions, want 0", len(unused))
+	}
+
+	_, err = player.PostJSON(ctx, url, map[string]any{"out_order_no": "no-2"})
+	if err == nil {
+		t.Fatal("replay PostJSON() with different body should fail")
+	}
+	for _, want := range []string{`- body   "out_order_no": "no-1"`, `+ body   "out_order_no": "no-2"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("mismatch error = %q, want it to contain %q", err, want)
+		}
+	}
+
+	bodyless, err := NewReplayRequest(path, WithMatcher(MatchMethod|MatchURL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = bodyless.PostJSON(ctx, url, map[string]any{"out_order_no": "no-2"}); err != nil {
+		t.Errorf("replay without body matching error = %v", err)
+	}
+}
+
+func TestCassetteRequestScrubError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	var (
+		ctx  = context.Background()
+		path = filepath.Join(t.TempDir(), "oauth.json")
+		url  = server.URL + "/oauth/access_token/?client_key=ck&client_secret=live-secret&code=live-code"
+	)
+	recorder := NewRecordRequest(path, NewDefaultRequest("accessTokenKey"))
+	if _, err := recorder.Get(ctx, url); err == nil {
+		t.Fatal("record Get() on closed server error = nil")
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"live-secret", "live-code"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette error contains unscrubbed secret %q", secret)
+		}
+	}
+	if !strings.Contains(string(data), "client_key=ck") {
+		t.Errorf("cassette error lost non-sensitive query: %s", data)
+	}
+
+	player, err := NewReplayRequest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = player.Get(ctx, url); err == nil || strings.Contains(err.Error(), "live-secret") {
+		t.Errorf("replay Get() error = %v, want scrubbed recorded error", err)
+	}
+}
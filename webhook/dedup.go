@@ -0,0 +1,208 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package webhook
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/minidrama/drama"
+	"github.com/houseme/bytedance/pay/asyncnotify"
+	"github.com/houseme/bytedance/payment/constant"
+	"github.com/houseme/bytedance/utility/cache"
+)
+
+const (
+	// SourcePay 担保支付回调
+	SourcePay = "pay"
+	// SourceDrama 短剧回调
+	SourceDrama = "drama"
+	// SourcePayment 旧版担保支付回调
+	SourcePayment = "payment"
+
+	// DefaultRetention 默认的去重记录保留时长，需覆盖平台的重试周期
+	DefaultRetention = 72 * time.Hour
+)
+
+// idFields 各来源、各回调类型用于标识业务单据的字段，多个字段共同组成业务 ID
+var idFields = map[string]map[string][]string{
+	SourcePay: {
+		asyncnotify.AsyncPay:          {"order_id"},
+		asyncnotify.AsyncSettle:       {"settle_id"},
+		asyncnotify.AsyncRefund:       {"refund_id"}, // 新交易系统退款回调没有 refund_no，平台退款单号为 refund_id
+		asyncnotify.AsyncWithdraw:     {"order_id"},
+		asyncnotify.AsyncTransfer:     {"order_id"},
+		asyncnotify.AsyncSettleFinish: {"order_id"},
+	},
+	SourceDrama: {
+		drama.AlbumAudit:   {"album_id", "version"},
+		drama.EpisodeAudit: {"episode_id", "version"},
+		drama.UploadVideo:  {"open_video_id"},
+	},
+	SourcePayment: {
+		constant.AsyncPay:      {"cp_orderno"},
+		constant.AsyncSettle:   {"settle_no"},
+		constant.AsyncRefund:   {"refund_no"},
+		constant.AsyncWithdraw: {"order_id"},
+	},
+}
+
+// statusFields 回调中表示处理结果的字段，同一单据状态变化后的回调不会被当作重复
+var statusFields = []string{"status", "audit_status", "success"}
+
+// Dedup 回调去重，记录已处理的回调，平台重试时直接应答成功而不重复执行业务逻辑。
+// 同一 key 的并发回调在进程内串行执行；cache.Cache 没有原子的 set-if-absent，多实例部署时并发投递仍可能各执行一次
+type Dedup struct {
+	cache     cache.Cache
+	keyPrefix string
+	retention time.Duration
+
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+// keyLock 单个 key 的锁，refs 为等待或持有该锁的数量，为 0 时删除
+type keyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+type dedupOptions struct {
+	keyPrefix string
+	retention time.Duration
+}
+
+// DedupOption dedup option
+type DedupOption func(*dedupOptions)
+
+// WithRetention 设置去重记录的保留时长
+func WithRetention(retention time.Duration) DedupOption {
+	return func(o *dedupOptions) {
+		o.retention = retention
+	}
+}
+
+// WithKeyPrefix 设置缓存 key 前缀
+func WithKeyPrefix(keyPrefix string) DedupOption {
+	return func(o *dedupOptions) {
+		o.keyPrefix = keyPrefix
+	}
+}
+
+// NewDedup create dedup
+func NewDedup(c cache.Cache, opts ...DedupOption) *Dedup {
+	op := dedupOptions{
+		keyPrefix: config.CacheKeyPrefix,
+		retention: DefaultRetention,
+	}
+	for _, option := range opts {
+		option(&op)
+	}
+	return &Dedup{cache: c, keyPrefix: op.keyPrefix, retention: op.retention, locks: make(map[string]*keyLock)}
+}
+
+// Key 生成去重 key：来源、回调类型、业务 ID 与状态，无法识别业务 ID 时使用 msg 的摘要
+func Key(source, typ, msg string) string {
+	var fields map[string]any
+	decoder := json.NewDecoder(strings.NewReader(msg))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err == nil {
+		var ids []string
+		for _, name := range idFields[source][typ] {
+			if v, ok := fields[name]; ok && fmt.Sprint(v) != "" {
+				ids = append(ids, fmt.Sprint(v))
+			}
+		}
+		if len(ids) > 0 {
+			var status string
+			for _, name := range statusFields {
+				if v, ok := fields[name]; ok {
+					status = fmt.Sprint(v)
+					break
+				}
+			}
+			return strings.Join([]string{source, typ, strings.Join(ids, "."), status}, ":")
+		}
+	}
+	return fmt.Sprintf("%s:%s:%x", source, typ, sha1.Sum([]byte(msg)))
+}
+
+// cacheKey 缓存 key
+func (d *Dedup) cacheKey(key string) string {
+	return d.keyPrefix + "_webhook_" + key
+}
+
+// Processed 回调是否已处理
+func (d *Dedup) Processed(ctx context.Context, key string) bool {
+	return d.cache.IsExist(ctx, d.cacheKey(key))
+}
+
+// MarkProcessed 标记回调已处理
+func (d *Dedup) MarkProcessed(ctx context.Context, key string) error {
+	return d.cache.Set(ctx, d.cacheKey(key), time.Now().Unix(), d.retention)
+}
+
+// Forget 删除处理记录，下次回调会重新处理
+func (d *Dedup) Forget(ctx context.Context, key string) error {
+	return d.cache.Delete(ctx, d.cacheKey(key))
+}
+
+// Do 回调未处理时执行 fn 并在成功后标记已处理，返回 processed 为 true 表示此前已处理，fn 未执行。
+// 同一 key 的并发调用会等待前一次执行结束，前一次成功后不再执行 fn
+func (d *Dedup) Do(ctx context.Context, key string, fn func() error) (processed bool, err error) {
+	unlock := d.lock(key)
+	defer unlock()
+
+	if d.Processed(ctx, key) {
+		return true, nil
+	}
+	if err = fn(); err != nil {
+		return false, err
+	}
+	return false, d.MarkProcessed(ctx, key)
+}
+
+// lock 获取 key 的进程内锁，返回解锁函数
+func (d *Dedup) lock(key string) func() {
+	d.mu.Lock()
+	l, ok := d.locks[key]
+	if !ok {
+		l = &keyLock{}
+		d.locks[key] = l
+	}
+	l.refs++
+	d.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		d.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(d.locks, key)
+		}
+		d.mu.Unlock()
+	}
+}
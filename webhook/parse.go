@@ -0,0 +1,83 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/houseme/bytedance/minidrama/drama"
+	"github.com/houseme/bytedance/pay/asyncnotify"
+	"github.com/houseme/bytedance/payment/trade"
+)
+
+// maxBodySize 回调报文的最大长度
+const maxBodySize = 1 << 20
+
+// envelope 担保支付与短剧回调的报文主体
+type envelope struct {
+	Version string `json:"version"`
+	Msg     string `json:"msg"`
+	Type    string `json:"type"`
+}
+
+// readBody 读取请求体
+func readBody(r *http.Request) ([]byte, error) {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+	return io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+}
+
+// ParsePayRequest 将担保支付回调请求解析为 asyncnotify.AsyncRequest，签名相关字段取自 Byte-* 请求头
+func ParsePayRequest(r *http.Request) (*asyncnotify.AsyncRequest, error) {
+	return asyncnotify.ParseRequest(r)
+}
+
+// ParseDramaRequest 将短剧回调请求解析为 drama.AsyncRequest，报文格式与担保支付回调相同，由 asyncnotify.ParseRequest 解析
+func ParseDramaRequest(r *http.Request) (*drama.AsyncRequest, error) {
+	req, err := asyncnotify.ParseRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	return &drama.AsyncRequest{
+		Content:       req.Content,
+		Msg:           req.Msg,
+		Type:          req.Type,
+		Version:       req.Version,
+		ByteTimestamp: req.ByteTimestamp,
+		ByteNonceStr:  req.ByteNonceStr,
+		ByteSignature: req.ByteSignature,
+	}, nil
+}
+
+// ParsePaymentRequest 将旧版担保支付回调请求解析为 payment/trade.AsyncRequest
+func ParsePaymentRequest(r *http.Request) (*trade.AsyncRequest, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	req := new(trade.AsyncRequest)
+	if err = json.Unmarshal(body, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
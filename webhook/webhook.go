@@ -0,0 +1,502 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+// Package webhook 抖音开放平台回调通知的 http.Handler，负责解析请求、验签、按回调类型分发并写入应答
+//
+//	router := webhook.New(
+//		webhook.WithPay(payClient.AsyncNotify()),
+//		webhook.WithDrama(miniDrama.Drama()),
+//	)
+//	router.OnPayment(func(ctx context.Context, data *asyncnotify.PaymentData) error { ... })
+//	http.Handle("/douyin/notify", router)
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/houseme/bytedance/minidrama/drama"
+	"github.com/houseme/bytedance/pay/asyncnotify"
+	"github.com/houseme/bytedance/payment/constant"
+	"github.com/houseme/bytedance/payment/trade"
+	"github.com/houseme/bytedance/utility/logger"
+)
+
+const (
+	// ErrNoSuccess 处理成功
+	ErrNoSuccess = 0
+	// ErrNoFailedToCheckTheSignature 验签失败
+	ErrNoFailedToCheckTheSignature = 400
+	// ErrNoRequestParameterError 请求参数错误
+	ErrNoRequestParameterError = 401
+	// ErrNoSystemError 处理失败，平台会重试回调
+	ErrNoSystemError = 10000
+
+	// ErrTipsSuccess 处理成功提示
+	ErrTipsSuccess = "success"
+)
+
+// ErrRefundAuditNotHandled 未通过 OnRefundAudit 注册退款申请回调的处理函数，应答成功会被平台视为审核通过
+var ErrRefundAuditNotHandled = errors.New("webhook: refund audit handler is not registered")
+
+// Ack 回调应答，err_no 非 0 时平台会重试
+type Ack struct {
+	ErrNo   int    `json:"err_no"`
+	ErrTips string `json:"err_tips"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// PayHandlerFunc 担保支付回调处理函数，resp 为验签并解析后的结果
+type PayHandlerFunc func(ctx context.Context, req *asyncnotify.AsyncRequest, resp *asyncnotify.AsyncResponse) error
+
+// DramaHandlerFunc 短剧回调处理函数，resp 为验签并解析后的结果
+type DramaHandlerFunc func(ctx context.Context, req *drama.AsyncRequest, resp *drama.AsyncResponse) error
+
+// PaymentHandlerFunc 旧版担保支付回调处理函数，req 已通过验签
+type PaymentHandlerFunc func(ctx context.Context, req *trade.AsyncRequest) error
+
+// Router 回调路由
+type Router struct {
+	pay     asyncnotify.IAsyncNotify
+	drama   drama.IDrama
+	payment trade.ITrade
+	dedup   *Dedup
+	logger  logger.ILogger
+
+	mu              sync.RWMutex
+	payHandlers     map[string]PayHandlerFunc
+	dramaHandlers   map[string]DramaHandlerFunc
+	paymentHandlers map[string]PaymentHandlerFunc
+}
+
+type options struct {
+	pay     asyncnotify.IAsyncNotify
+	drama   drama.IDrama
+	payment trade.ITrade
+	dedup   *Dedup
+	logger  logger.ILogger
+}
+
+// Option router option
+type Option func(*options)
+
+// WithPay 设置担保支付回调的验签服务，通常为 pay.Pay.AsyncNotify()
+func WithPay(pay asyncnotify.IAsyncNotify) Option {
+	return func(o *options) {
+		o.pay = pay
+	}
+}
+
+// WithDrama 设置短剧回调的验签服务，通常为 minidrama.MiniDrama.Drama()
+func WithDrama(drama drama.IDrama) Option {
+	return func(o *options) {
+		o.drama = drama
+	}
+}
+
+// WithPayment 设置旧版担保支付回调的验签服务，通常为 payment.Payment.Trade()
+func WithPayment(payment trade.ITrade) Option {
+	return func(o *options) {
+		o.payment = payment
+	}
+}
+
+// WithDedup 设置回调去重，已处理的回调直接应答成功，不再调用处理函数
+func WithDedup(dedup *Dedup) Option {
+	return func(o *options) {
+		o.dedup = dedup
+	}
+}
+
+// WithLogger set logger
+func WithLogger(logger logger.ILogger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// New create router
+func New(opts ...Option) *Router {
+	op := options{
+		logger: logger.NewDefaultLogger(),
+	}
+	for _, option := range opts {
+		option(&op)
+	}
+	return &Router{
+		pay:             op.pay,
+		drama:           op.drama,
+		payment:         op.payment,
+		dedup:           op.dedup,
+		logger:          op.logger,
+		payHandlers:     make(map[string]PayHandlerFunc),
+		dramaHandlers:   make(map[string]DramaHandlerFunc),
+		paymentHandlers: make(map[string]PaymentHandlerFunc),
+	}
+}
+
+// HandlePay 注册担保支付回调处理函数，typ 为 asyncnotify.AsyncPay 等回调类型
+func (rt *Router) HandlePay(typ string, h PayHandlerFunc) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.payHandlers[typ] = h
+}
+
+// HandleDrama 注册短剧回调处理函数，typ 为 drama.AlbumAudit 等回调类型
+func (rt *Router) HandleDrama(typ string, h DramaHandlerFunc) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.dramaHandlers[typ] = h
+}
+
+// HandlePayment 注册旧版担保支付回调处理函数，typ 为 constant.AsyncPay 等回调类型
+func (rt *Router) HandlePayment(typ string, h PaymentHandlerFunc) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.paymentHandlers[typ] = h
+}
+
+// OnPayment 担保支付支付结果回调
+func (rt *Router) OnPayment(h func(ctx context.Context, data *asyncnotify.PaymentData) error) {
+	rt.HandlePay(asyncnotify.AsyncPay, func(ctx context.Context, _ *asyncnotify.AsyncRequest, resp *asyncnotify.AsyncResponse) error {
+		return h(ctx, resp.PaymentData)
+	})
+}
+
+// OnSettle 担保支付分账结果回调
+func (rt *Router) OnSettle(h func(ctx context.Context, data *asyncnotify.SettleData) error) {
+	rt.HandlePay(asyncnotify.AsyncSettle, func(ctx context.Context, _ *asyncnotify.AsyncRequest, resp *asyncnotify.AsyncResponse) error {
+		return h(ctx, resp.SettleData)
+	})
+}
+
+// OnRefund 担保支付退款结果回调
+func (rt *Router) OnRefund(h func(ctx context.Context, data *asyncnotify.RefundData) error) {
+	rt.HandlePay(asyncnotify.AsyncRefund, func(ctx context.Context, _ *asyncnotify.AsyncRequest, resp *asyncnotify.AsyncResponse) error {
+		return h(ctx, resp.RefundData)
+	})
+}
+
+// OnWithdraw 担保支付提现结果回调
+func (rt *Router) OnWithdraw(h func(ctx context.Context, data *asyncnotify.WithdrawData) error) {
+	rt.HandlePay(asyncnotify.AsyncWithdraw, func(ctx context.Context, _ *asyncnotify.AsyncRequest, resp *asyncnotify.AsyncResponse) error {
+		return h(ctx, resp.WithdrawData)
+	})
+}
+
+// OnTransfer 担保支付转账结果回调
+func (rt *Router) OnTransfer(h func(ctx context.Context, data *asyncnotify.TransferData) error) {
+	rt.HandlePay(asyncnotify.AsyncTransfer, func(ctx context.Context, _ *asyncnotify.AsyncRequest, resp *asyncnotify.AsyncResponse) error {
+		return h(ctx, resp.TransferData)
+	})
+}
+
+// OnSettleFinish 担保支付自动分账完成回调
+func (rt *Router) OnSettleFinish(h func(ctx context.Context, data *asyncnotify.SettleFinishData) error) {
+	rt.HandlePay(asyncnotify.AsyncSettleFinish, func(ctx context.Context, _ *asyncnotify.AsyncRequest, resp *asyncnotify.AsyncResponse) error {
+		return h(ctx, resp.SettleFinishData)
+	})
+}
+
+// OnRefundAudit 担保支付退款申请（审核）回调，h 返回的审核结果作为应答 data 返回；
+// 该回调不做去重，平台重试时会再次调用 h，h 应对同一 refund_id 返回相同的结果。
+// 未注册时退款申请回调应答系统错误，不会默认通过审核
+func (rt *Router) OnRefundAudit(h func(ctx context.Context, data *asyncnotify.RefundAuditData) (*asyncnotify.RefundAuditDecision, error)) {
+	rt.HandlePay(asyncnotify.AsyncRefundAudit, func(ctx context.Context, _ *asyncnotify.AsyncRequest, resp *asyncnotify.AsyncResponse) (err error) {
+		resp.RefundAuditDecision, err = h(ctx, resp.RefundAuditData)
+		return err
+	})
+}
+
+// OnAlbumAudit 短剧审核结果回调
+func (rt *Router) OnAlbumAudit(h func(ctx context.Context, data *drama.AsyncAlbumAudit) error) {
+	rt.HandleDrama(drama.AlbumAudit, func(ctx context.Context, _ *drama.AsyncRequest, resp *drama.AsyncResponse) error {
+		return h(ctx, resp.AlbumAudit)
+	})
+}
+
+// OnEpisodeAudit 剧集审核结果回调
+func (rt *Router) OnEpisodeAudit(h func(ctx context.Context, data *drama.AsyncEpisodeAudit) error) {
+	rt.HandleDrama(drama.EpisodeAudit, func(ctx context.Context, _ *drama.AsyncRequest, resp *drama.AsyncResponse) error {
+		return h(ctx, resp.EpisodeAudit)
+	})
+}
+
+// OnUploadVideo 视频上传结果回调
+func (rt *Router) OnUploadVideo(h func(ctx context.Context, data *drama.AsyncUploadVideo) error) {
+	rt.HandleDrama(drama.UploadVideo, func(ctx context.Context, _ *drama.AsyncRequest, resp *drama.AsyncResponse) error {
+		return h(ctx, resp.UploadVideo)
+	})
+}
+
+// OnPaymentPay 旧版担保支付支付结果回调
+func (rt *Router) OnPaymentPay(h func(ctx context.Context, data *trade.AsyncPaymentData) error) {
+	rt.HandlePayment(constant.AsyncPay, paymentHandler(h))
+}
+
+// OnPaymentSettle 旧版担保支付分账结果回调
+func (rt *Router) OnPaymentSettle(h func(ctx context.Context, data *trade.AsyncSettleData) error) {
+	rt.HandlePayment(constant.AsyncSettle, paymentHandler(h))
+}
+
+// OnPaymentRefund 旧版担保支付退款结果回调
+func (rt *Router) OnPaymentRefund(h func(ctx context.Context, data *trade.AsyncRefundData) error) {
+	rt.HandlePayment(constant.AsyncRefund, paymentHandler(h))
+}
+
+// OnPaymentWithdraw 旧版担保支付提现结果回调
+func (rt *Router) OnPaymentWithdraw(h func(ctx context.Context, data *trade.AsyncWithdrawData) error) {
+	rt.HandlePayment(constant.AsyncWithdraw, paymentHandler(h))
+}
+
+// paymentHandler 将 msg 解析为 T 后调用 h
+func paymentHandler[T any](h func(ctx context.Context, data *T) error) PaymentHandlerFunc {
+	return func(ctx context.Context, req *trade.AsyncRequest) error {
+		data := new(T)
+		if err := json.Unmarshal([]byte(req.Msg), data); err != nil {
+			return err
+		}
+		return h(ctx, data)
+	}
+}
+
+// Pay 担保支付回调的 http.Handler
+func (rt *Router) Pay() http.Handler {
+	return http.HandlerFunc(rt.servePay)
+}
+
+// Drama 短剧回调的 http.Handler
+func (rt *Router) Drama() http.Handler {
+	return http.HandlerFunc(rt.serveDrama)
+}
+
+// Payment 旧版担保支付回调的 http.Handler
+func (rt *Router) Payment() http.Handler {
+	return http.HandlerFunc(rt.servePayment)
+}
+
+// ServeHTTP implements http.Handler，根据请求自动识别回调来源：
+// 携带 Byte-Signature 请求头且 version 为 2.0 的为短剧回调，其余携带该请求头的为担保支付回调，否则为旧版担保支付回调
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(r)
+	if err != nil {
+		writeAck(w, ErrNoRequestParameterError, err.Error())
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if r.Header.Get(asyncnotify.HeaderSignature) == "" {
+		rt.servePayment(w, r)
+		return
+	}
+	var env envelope
+	if err = json.Unmarshal(body, &env); err == nil && env.Version == drama.DefaultAsyncVersion {
+		rt.serveDrama(w, r)
+		return
+	}
+	rt.servePay(w, r)
+}
+
+func (rt *Router) servePay(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if rt.pay == nil {
+		writeAck(w, ErrNoSystemError, "pay callback is not configured")
+		return
+	}
+	req, err := ParsePayRequest(r)
+	if err != nil {
+		writeAck(w, ErrNoRequestParameterError, err.Error())
+		return
+	}
+	resp, err := rt.pay.AsyncNotify(ctx, req)
+	if err != nil || resp == nil || resp.ErrNo != asyncnotify.ErrNoSuccess {
+		var ack Ack
+		if resp != nil {
+			ack = Ack{ErrNo: resp.ErrNo, ErrTips: resp.ErrTips}
+		}
+		rt.reject(ctx, w, req.Type, errAck(ack, err))
+		return
+	}
+
+	rt.mu.RLock()
+	h, ok := rt.payHandlers[req.Type]
+	rt.mu.RUnlock()
+	if !ok && req.Type == asyncnotify.AsyncRefundAudit {
+		rt.respond(ctx, w, req.Type, resp.Finish(ctx, ErrRefundAuditNotHandled))
+		return
+	}
+	if !ok {
+		rt.respond(ctx, w, req.Type, resp.Finish(ctx, nil))
+		return
+	}
+	if req.Type == asyncnotify.AsyncRefundAudit {
+		rt.respondRefundAudit(ctx, w, resp, resp.Finish(ctx, h(ctx, req, resp)))
+		return
+	}
+	rt.dispatch(ctx, w, SourcePay, req.Type, req.Msg, func() error {
+		return h(ctx, req, resp)
+	}, resp.Finish)
+}
+
+// respondRefundAudit 写入退款申请回调应答，审核结果作为 data 返回
+func (rt *Router) respondRefundAudit(ctx context.Context, w http.ResponseWriter, resp *asyncnotify.AsyncResponse, err error) {
+	if err != nil {
+		rt.respond(ctx, w, asyncnotify.AsyncRefundAudit, err)
+		return
+	}
+	ack := resp.RefundAuditAck()
+	if ack.ErrNo != asyncnotify.ErrNoSuccess {
+		rt.logger.Errorf(ctx, "webhook handle callback type: %s, error: %s", asyncnotify.AsyncRefundAudit, ack.ErrTips)
+	}
+	out := Ack{ErrNo: ack.ErrNo, ErrTips: ack.ErrTips}
+	if ack.Data != nil {
+		out.Data = ack.Data
+	}
+	writeJSON(w, out)
+}
+
+func (rt *Router) serveDrama(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if rt.drama == nil {
+		writeAck(w, ErrNoSystemError, "drama callback is not configured")
+		return
+	}
+	req, err := ParseDramaRequest(r)
+	if err != nil {
+		writeAck(w, ErrNoRequestParameterError, err.Error())
+		return
+	}
+	resp, err := rt.drama.AsyncNotify(ctx, req)
+	if err != nil || resp == nil || resp.ErrNo != drama.ErrNoSuccess {
+		var ack Ack
+		if resp != nil {
+			ack = Ack{ErrNo: resp.ErrNo, ErrTips: resp.ErrTips}
+		}
+		rt.reject(ctx, w, req.Type, errAck(ack, err))
+		return
+	}
+
+	rt.mu.RLock()
+	h, ok := rt.dramaHandlers[req.Type]
+	rt.mu.RUnlock()
+	if !ok {
+		rt.respond(ctx, w, req.Type, resp.Finish(ctx, nil))
+		return
+	}
+	rt.dispatch(ctx, w, SourceDrama, req.Type, req.Msg, func() error {
+		return h(ctx, req, resp)
+	}, resp.Finish)
+}
+
+func (rt *Router) servePayment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if rt.payment == nil {
+		writeAck(w, ErrNoSystemError, "payment callback is not configured")
+		return
+	}
+	req, err := ParsePaymentRequest(r)
+	if err != nil {
+		writeAck(w, ErrNoRequestParameterError, err.Error())
+		return
+	}
+	resp, err := rt.payment.AsyncNotify(ctx, req)
+	if err != nil || resp == nil || resp.ErrNo != constant.Success {
+		var ack Ack
+		if resp != nil {
+			ack = Ack{ErrNo: resp.ErrNo, ErrTips: resp.ErrTips}
+		}
+		rt.reject(ctx, w, req.Type, errAck(ack, err))
+		return
+	}
+
+	rt.mu.RLock()
+	h, ok := rt.paymentHandlers[req.Type]
+	rt.mu.RUnlock()
+	if !ok {
+		rt.respond(ctx, w, req.Type, nil)
+		return
+	}
+	rt.dispatch(ctx, w, SourcePayment, req.Type, req.Msg, func() error {
+		return h(ctx, req)
+	}, nil)
+}
+
+// errAck 验签失败或出错时的应答，缺省为系统错误
+func errAck(ack Ack, err error) Ack {
+	if ack.ErrNo == ErrNoSuccess {
+		ack.ErrNo = ErrNoSystemError
+	}
+	if ack.ErrTips == "" || ack.ErrTips == ErrTipsSuccess {
+		ack.ErrTips = "system error"
+		if err != nil {
+			ack.ErrTips = err.Error()
+		}
+	}
+	return ack
+}
+
+// reject 验签未通过
+func (rt *Router) reject(ctx context.Context, w http.ResponseWriter, typ string, ack Ack) {
+	rt.logger.Warningf(ctx, "webhook reject callback type: %s, err_no: %d, err_tips: %s", typ, ack.ErrNo, ack.ErrTips)
+	writeAck(w, ack.ErrNo, ack.ErrTips)
+}
+
+// dispatch 执行处理函数并写入应答，配置了去重时跳过已处理的回调；finish 不为 nil 时以处理结果结束防重放占用
+func (rt *Router) dispatch(ctx context.Context, w http.ResponseWriter, source, typ, msg string, fn func() error, finish func(context.Context, error) error) {
+	var err error
+	if rt.dedup == nil {
+		err = fn()
+	} else {
+		var processed bool
+		if processed, err = rt.dedup.Do(ctx, Key(source, typ, msg), fn); processed {
+			rt.logger.Infof(ctx, "webhook callback already processed, source: %s, type: %s", source, typ)
+		}
+	}
+	if finish != nil {
+		err = finish(ctx, err)
+	}
+	rt.respond(ctx, w, typ, err)
+}
+
+// respond 根据处理结果写入应答，处理失败时返回非 0 err_no 触发平台重试
+func (rt *Router) respond(ctx context.Context, w http.ResponseWriter, typ string, err error) {
+	if err != nil {
+		rt.logger.Errorf(ctx, "webhook handle callback type: %s, error: %v", typ, err)
+		writeAck(w, ErrNoSystemError, err.Error())
+		return
+	}
+	writeAck(w, ErrNoSuccess, ErrTipsSuccess)
+}
+
+// writeAck 写入应答
+func writeAck(w http.ResponseWriter, errNo int, errTips string) {
+	writeJSON(w, Ack{ErrNo: errNo, ErrTips: errTips})
+}
+
+// writeJSON 写入 JSON 应答
+func writeJSON(w http.ResponseWriter, ack Ack) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(ack)
+}
@@ -0,0 +1,255 @@
+/*
+ * Copyright Bytedance Author(https://houseme.github.io/bytedance/). All Rights Reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * You can obtain one at https://github.com/houseme/bytedance.
+ *
+ */
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/houseme/bytedance/config"
+	"github.com/houseme/bytedance/credential"
+	"github.com/houseme/bytedance/minidrama/drama"
+	"github.com/houseme/bytedance/pay/asyncnotify"
+	"github.com/houseme/bytedance/payment/trade"
+	"github.com/houseme/bytedance/utility/cache"
+	"github.com/houseme/bytedance/utility/callbacksim"
+)
+
+func TestRouter(t *testing.T) {
+	sim, err := callbacksim.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	ctxCfg := &credential.ContextConfig{
+		Config: config.New(ctx, config.WithPublicKey(sim.PublicKey()), config.WithToken(sim.Token())),
+	}
+	router := New(
+		WithPay(asyncnotify.NewAsyncNotify(ctxCfg)),
+		WithDrama(drama.NewDrama(ctxCfg)),
+		WithPayment(trade.NewTrade(ctxCfg)),
+	)
+
+	var (
+		paid    []string
+		albums  []int64
+		refunds []string
+	)
+	router.OnPayment(func(_ context.Context, data *asyncnotify.PaymentData) error {
+		if data.OrderID == "fail" {
+			return errors.New("db down")
+		}
+		paid = append(paid, data.OrderID)
+		return nil
+	})
+	router.OnAlbumAudit(func(_ context.Context, data *drama.AsyncAlbumAudit) error {
+		albums = append(albums, data.AlbumID)
+		return nil
+	})
+	router.OnPaymentRefund(func(_ context.Context, data *trade.AsyncRefundData) error {
+		refunds = append(refunds, data.CpRefundNo)
+		return nil
+	})
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	payReq, _ := sim.Payment(&asyncnotify.PaymentData{OrderID: "ord-1"})
+	if ack, err := sim.DeliverPay(ctx, server.URL, payReq); err != nil || ack.ErrNo != ErrNoSuccess {
+		t.Errorf("pay callback ack = %+v, %v", ack, err)
+	}
+	failReq, _ := sim.Payment(&asyncnotify.PaymentData{OrderID: "fail"})
+	if ack, err := sim.DeliverPay(ctx, server.URL, failReq); err != nil || ack.ErrNo != ErrNoSystemError {
+		t.Errorf("failing handler ack = %+v, %v, want err_no %d", ack, err, ErrNoSystemError)
+	}
+	payReq.ByteSignature = failReq.ByteSignature
+	if ack, err := sim.DeliverPay(ctx, server.URL, payReq); err != nil || ack.ErrNo != ErrNoFailedToCheckTheSignature {
+		t.Errorf("forged callback ack = %+v, %v, want err_no %d", ack, err, ErrNoFailedToCheckTheSignature)
+	}
+
+	dramaReq, _ := sim.AlbumAudit(&drama.AsyncAlbumAudit{AlbumID: 7})
+	if ack, err := sim.DeliverDrama(ctx, server.URL, dramaReq); err != nil || ack.ErrNo != ErrNoSuccess {
+		t.Errorf("drama callback ack = %+v, %v", ack, err)
+	}
+	ecpayReq, _ := sim.EcpayRefund(&trade.AsyncRefundData{CpRefundNo: "rf-1"})
+	if ack, err := sim.DeliverEcpay(ctx, server.URL, ecpayReq); err != nil || ack.ErrNo != ErrNoSuccess {
+		t.Errorf("payment callback ack = %+v, %v", ack, err)
+	}
+
+	if len(paid) != 1 || len(albums) != 1 || albums[0] != 7 || len(refunds) != 1 || refunds[0] != "rf-1" {
+		t.Errorf("dispatched paid=%v albums=%v refunds=%v", paid, albums, refunds)
+	}
+}
+
+func TestRouterDedup(t *testing.T) {
+	sim, err := callbacksim.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	ctxCfg := &credential.ContextConfig{Config: config.New(ctx, config.WithPublicKey(sim.PublicKey()))}
+	router := New(
+		WithPay(asyncnotify.NewAsyncNotify(ctxCfg)),
+		WithDedup(NewDedup(cache.NewMemory())),
+	)
+	var credited int
+	router.OnPayment(func(context.Context, *asyncnotify.PaymentData) error {
+		credited++
+		return nil
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	for _, status := range []string{asyncnotify.StateSuccess, asyncnotify.StateSuccess, asyncnotify.StateCancel} {
+		req, _ := sim.Payment(&asyncnotify.PaymentData{OrderID: "ord-1", Status: status})
+		if ack, err := sim.DeliverPay(ctx, server.URL, req); err != nil || ack.ErrNo != ErrNoSuccess {
+			t.Fatalf("pay callback ack = %+v, %v", ack, err)
+		}
+	}
+	if credited != 2 {
+		t.Errorf("handler ran %d times, want 2 (retry skipped, new status handled)", credited)
+	}
+
+	if got, want := Key(SourceDrama, "album_audit", `{"album_id":7,"version":2,"audit_status":2}`), "drama:album_audit:7.2:2"; got != want {
+		t.Errorf("Key() = %q, want %q", got, want)
+	}
+}
+
+func TestDedupConcurrent(t *testing.T) {
+	ctx := context.Background()
+	dedup := NewDedup(cache.NewMemory())
+	var (
+		ran       atomic.Int32
+		processed atomic.Int32
+		wg        sync.WaitGroup
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			done, err := dedup.Do(ctx, "pay:payment:ord-1:SUCCESS", func() error {
+				ran.Add(1)
+				time.Sleep(5 * time.Millisecond)
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+			if done {
+				processed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if ran.Load() != 1 || processed.Load() != 7 {
+		t.Errorf("fn ran %d times, %d reported processed, want 1 and 7", ran.Load(), processed.Load())
+	}
+
+	// 失败后不标记，下一次投递重新执行
+	failed := errors.New("db down")
+	if _, err := dedup.Do(ctx, "k", func() error { return failed }); !errors.Is(err, failed) {
+		t.Fatalf("Do() err = %v", err)
+	}
+	if done, err := dedup.Do(ctx, "k", func() error { return nil }); done || err != nil {
+		t.Fatalf("Do() after failure = %v, %v", done, err)
+	}
+}
+
+func TestRouterReplayRetry(t *testing.T) {
+	sim, err := callbacksim.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	ctxCfg := &credential.ContextConfig{Config: config.New(ctx,
+		config.WithPublicKey(sim.PublicKey()), config.WithCache(cache.NewMemory()), config.WithNonceTTL(time.Hour))}
+	router := New(WithPay(asyncnotify.NewAsyncNotify(ctxCfg)))
+	var calls int
+	router.OnPayment(func(context.Context, *asyncnotify.PaymentData) error {
+		if calls++; calls == 1 {
+			return errors.New("db down")
+		}
+		return nil
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	req, _ := sim.Payment(&asyncnotify.PaymentData{OrderID: "ord-1"})
+	for i, want := range []int{ErrNoSystemError, ErrNoSuccess, ErrNoFailedToCheckTheSignature} {
+		if ack, err := sim.DeliverPay(ctx, server.URL, req); err != nil || ack.ErrNo != want {
+			t.Errorf("delivery %d ack = %+v, %v, want err_no %d", i, ack, err, want)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2", calls)
+	}
+}
+
+func TestRouterRefundAudit(t *testing.T) {
+	sim, err := callbacksim.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	ctxCfg := &credential.ContextConfig{Config: config.New(ctx, config.WithPublicKey(sim.PublicKey()))}
+	router := New(
+		WithPay(asyncnotify.NewAsyncNotify(ctxCfg)),
+		WithDedup(NewDedup(cache.NewMemory())),
+	)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	unhandled, _ := sim.RefundAudit(&asyncnotify.RefundAuditData{RefundID: "rfd-0", RefundTotalAmount: 50, NeedRefundAudit: 1})
+	if ack, err := sim.DeliverPay(ctx, server.URL, unhandled); err != nil || ack.ErrNo != ErrNoSystemError {
+		t.Errorf("unhandled refund audit ack = %+v, %v, want err_no %d", ack, err, ErrNoSystemError)
+	}
+
+	router.OnRefundAudit(func(_ context.Context, data *asyncnotify.RefundAuditData) (*asyncnotify.RefundAuditDecision, error) {
+		if data.RefundTotalAmount > 100 {
+			return asyncnotify.RejectRefund("out-"+data.RefundID, "已发货"), nil
+		}
+		return asyncnotify.ApproveRefund("out-" + data.RefundID), nil
+	})
+
+	cases := []struct {
+		amount int
+		want   asyncnotify.RefundAuditDecision
+	}{
+		{50, asyncnotify.RefundAuditDecision{OutRefundNo: "out-rfd-1", RefundAuditStatus: asyncnotify.RefundAuditApprove}},
+		{500, asyncnotify.RefundAuditDecision{OutRefundNo: "out-rfd-1", RefundAuditStatus: asyncnotify.RefundAuditReject, DenyMessage: "已发货"}},
+	}
+	for _, c := range cases {
+		req, _ := sim.RefundAudit(&asyncnotify.RefundAuditData{RefundID: "rfd-1", RefundTotalAmount: c.amount, NeedRefundAudit: 1})
+		ack, err := sim.DeliverPay(ctx, server.URL, req)
+		if err != nil || ack.ErrNo != ErrNoSuccess {
+			t.Fatalf("refund audit ack = %+v, %v", ack, err)
+		}
+		var body asyncnotify.RefundAuditAck
+		if err = json.Unmarshal(ack.Body, &body); err != nil || body.Data == nil || *body.Data != c.want {
+			t.Errorf("refund audit decision = %s, want %+v", ack.Body, c.want)
+		}
+	}
+}